@@ -9,15 +9,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"time"
 
 	cli "github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	joetls "github.com/Carbonfrost/joe-cli-http/tls"
 	"github.com/Carbonfrost/joe-cli-http/uritemplates"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
 	"github.com/Carbonfrost/joe-cli/extensions/provider"
 	"github.com/Carbonfrost/pastiche/pkg/contextual"
+	"github.com/Carbonfrost/pastiche/pkg/credential"
 	"github.com/Carbonfrost/pastiche/pkg/grpcclient"
 	"github.com/Carbonfrost/pastiche/pkg/internal/build"
 	"github.com/Carbonfrost/pastiche/pkg/model"
@@ -36,6 +39,13 @@ type Client struct {
 	filter          Filter
 	includeMetadata bool
 
+	// onHistory is notified with the history of each response once it
+	// has been downloaded
+	onHistory   func(*history)
+	lastHistory *history
+
+	expectations []*model.Expectation
+
 	locationResolver httpclient.LocationResolver
 }
 
@@ -59,6 +69,8 @@ func New(opts ...Option) *Client {
 		),
 		httpclient.WithDownloaderMiddleware(res.filterResponse),
 		httpclient.WithDownloaderMiddleware(res.historyLogMiddleware),
+		httpclient.WithDownloaderMiddleware(res.webResponse),
+		httpclient.WithDownloaderMiddleware(res.digestResponse),
 	)
 
 	res.http = client
@@ -66,6 +78,8 @@ func New(opts ...Option) *Client {
 		grpcclient.WithLocationResolver(
 			sr,
 		),
+		grpcclient.WithDownloaderMiddleware(res.filterResponse),
+		grpcclient.WithDownloaderMiddleware(res.historyLogMiddleware),
 	)
 	res.Action = defaultAction(res)
 	return res
@@ -88,7 +102,37 @@ func (c *Client) filterResponse(_ context.Context, d httpclient.Downloader) http
 }
 
 func (c *Client) historyLogMiddleware(_ context.Context, d httpclient.Downloader) httpclient.Downloader {
-	return newHistoryDownloader(d, c.historyLog)
+	return newHistoryDownloader(d, c.historyLog, c.notifyHistory)
+}
+
+// webResponse converts the response of a gRPC-Web or Connect call so that it
+// is filtered and logged as JSON
+func (c *Client) webResponse(ctx context.Context, d httpclient.Downloader) httpclient.Downloader {
+	if sr, ok := c.locationResolver.(*serviceResolver); ok && sr.web != nil {
+		return sr.web.DownloaderMiddleware(ctx, d)
+	}
+	return d
+}
+
+// digestResponse sends requests that use Digest auth again once the server
+// has responded with its challenge. The transport of the HTTP client is used
+// so that the request is sent the same way as the first time.
+func (c *Client) digestResponse(_ context.Context, d httpclient.Downloader) httpclient.Downloader {
+	transport := http.DefaultTransport
+	if c.http.Client != nil && c.http.Client.Transport != nil {
+		transport = c.http.Client.Transport
+	}
+	return &digestDownloader{
+		Downloader: d,
+		transport:  &digestTransport{Base: transport},
+	}
+}
+
+func (c *Client) notifyHistory(h *history) {
+	c.lastHistory = h
+	if c.onHistory != nil {
+		c.onHistory(h)
+	}
 }
 
 // Type gets the client type that was requested
@@ -122,6 +166,7 @@ func defaultAction(c *Client) cli.Action {
 		FilterRegistry,
 		FlagsAndArgs(),
 		ContextValue(c),
+		credential.ContextValue(fetchSpecToken, specScope),
 	)
 }
 
@@ -139,6 +184,8 @@ func FlagsAndArgs() cli.Action {
 			{Uses: SetFilter()},
 			{Uses: SetType()},
 			{Uses: SetIncludeMetadata()},
+			{Uses: SetExpect()},
+			{Uses: SetVarSets()},
 		}...),
 	)
 }
@@ -209,14 +256,23 @@ func (c *Client) historyLog(ctx context.Context, r *httpclient.Response) (*histo
 	if req != nil {
 		vars = req.Vars // TODO Would be better to separate input vars from compiled
 	}
+	var stream *historyStream
+	if s, ok := grpcclient.StreamFromContext(ctx); ok {
+		stream = &historyStream{
+			Index: s.Index,
+			Start: s.Start,
+		}
+	}
+
 	var responseBody bytes.Buffer
 	return &history{
 		Timestamp: time.Now(), // TODO To be persnickety, should be the exact request timing
 		URL:       fmt.Sprint(r.Request.URL),
-		Spec:      *resolver.root(ctx),
+		Spec:      resolver.spec(ctx),
 		Server:    resolver.server(ctx),
 		Response: historyResponse{
 			Headers:    r.Header,
+			Trailers:   r.Trailer,
 			Status:     r.Status,
 			StatusCode: r.StatusCode,
 			Body:       &historyResponseBody{&responseBody},
@@ -227,6 +283,7 @@ func (c *Client) historyLog(ctx context.Context, r *httpclient.Response) (*histo
 		},
 		Vars:    vars,
 		BaseURL: sprintURL(resolver.base),
+		Stream:  stream,
 	}, &responseBody
 }
 
@@ -244,7 +301,7 @@ func WithLocationResolver(value httpclient.LocationResolver) Option {
 
 // WithDefaultLocationResolver provides a client option which sets up the
 // default location resolver, which uses the CLI arguments and flags named
-// "service", "server", and "method"
+// "service", "server", "method", and "varset"
 func WithDefaultLocationResolver() Option {
 	sr := NewServiceResolver(
 		func(ctx context.Context) *model.Model {
@@ -254,6 +311,28 @@ func WithDefaultLocationResolver() Option {
 		lateBinding[string]("server"),
 		lateBinding[string]("method"),
 	)
+	sr.(*serviceResolver).varSets = lateBindingList("varset")
+	return WithLocationResolver(sr)
+}
+
+// WithFlowLocationResolver provides a client option which sets up the
+// location resolver used to run the steps of a flow. The service spec is
+// obtained from the step which is being run and the server and var sets from
+// the CLI flags named "server" and "varset"
+func WithFlowLocationResolver() Option {
+	sr := NewServiceResolver(
+		func(ctx context.Context) *model.Model {
+			return contextual.Workspace(ctx).Model()
+		},
+		func(context.Context) *model.ServiceSpec {
+			return new(model.ServiceSpec)
+		},
+		lateBinding[string]("server"),
+		func(context.Context) string {
+			return ""
+		},
+	)
+	sr.(*serviceResolver).varSets = lateBindingList("varset")
 	return WithLocationResolver(sr)
 }
 
@@ -263,7 +342,23 @@ func withBinding[V any](binder func(*Client, V) error, args []V) cli.Action {
 
 func lateBinding[V any](name string) func(context.Context) V {
 	return func(c context.Context) V {
-		ss := c.(*cli.Context).Value(name).(V)
+		ss := cli.FromContext(c).Value(name).(V)
 		return ss
 	}
 }
+
+func lateBindingList(name string) func(context.Context) []string {
+	return func(c context.Context) []string {
+		return cli.FromContext(c).List(name)
+	}
+}
+
+// httpTransport gets the transport for requests that the client sends
+// itself, which uses the TLS configuration of the command
+func httpTransport(ctx context.Context) http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg := joetls.FromContext(ctx); cfg != nil && cfg.Config != nil {
+		t.TLSClientConfig = cfg.Config.Clone()
+	}
+	return t
+}
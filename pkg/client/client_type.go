@@ -8,6 +8,8 @@ import (
 	"encoding"
 	"fmt"
 	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/grpcclient"
 )
 
 // Type enumerates the client types available to Pastiche client
@@ -17,16 +19,27 @@ const (
 	TypeUnspecified Type = iota
 	TypeHTTP
 	TypeGRPC
+	TypeGRPCWeb
+	TypeConnect
 	maxType
 )
 
 var (
 	typeLabels = [maxType]string{
-		"HTTP",
-		"GRPC",
+		TypeHTTP:    "HTTP",
+		TypeGRPC:    "GRPC",
+		TypeGRPCWeb: "GRPC-WEB",
+		TypeConnect: "CONNECT",
 	}
 )
 
+// webProtocols maps the client types which call gRPC services over HTTP to
+// their protocols
+var webProtocols = map[Type]string{
+	TypeGRPCWeb: grpcclient.ProtocolGRPCWeb,
+	TypeConnect: grpcclient.ProtocolConnect,
+}
+
 // String produces a textual representation of the Type
 func (t Type) String() string {
 	return typeLabels[t]
@@ -41,7 +54,7 @@ func (t Type) MarshalText() ([]byte, error) {
 func (t *Type) UnmarshalText(b []byte) error {
 	token := strings.TrimSpace(string(b))
 	for k, y := range typeLabels {
-		if strings.EqualFold(token, y) {
+		if y != "" && strings.EqualFold(token, y) {
 			*t = Type(k)
 			return nil
 		}
@@ -0,0 +1,38 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Type", func() {
+
+	DescribeTable("examples", func(text string, expected phttpclient.Type) {
+		var actual phttpclient.Type
+		Expect(actual.UnmarshalText([]byte(text))).To(Succeed())
+		Expect(actual).To(Equal(expected))
+		Expect(actual.String()).To(Equal(text))
+	},
+		Entry("HTTP", "HTTP", phttpclient.TypeHTTP),
+		Entry("GRPC", "GRPC", phttpclient.TypeGRPC),
+		Entry("GRPC-WEB", "GRPC-WEB", phttpclient.TypeGRPCWeb),
+		Entry("CONNECT", "CONNECT", phttpclient.TypeConnect),
+	)
+
+	It("parses ignoring case", func() {
+		var actual phttpclient.Type
+		Expect(actual.UnmarshalText([]byte("grpc-web"))).To(Succeed())
+		Expect(actual).To(Equal(phttpclient.TypeGRPCWeb))
+	})
+
+	It("returns error for unknown type", func() {
+		var actual phttpclient.Type
+		Expect(actual.UnmarshalText([]byte(""))).To(MatchError(`unknown client type ""`))
+	})
+})
@@ -0,0 +1,228 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+// digestChallenge is the parameters of the WWW-Authenticate header which
+// uses the Digest scheme
+type digestChallenge map[string]string
+
+// digestTransport sends requests and retries those that use Digest auth
+// once the server responds with its challenge. Requests are only sent once
+// to servers that don't require authentication.
+type digestTransport struct {
+	Base http.RoundTripper
+}
+
+type digestDownloader struct {
+	httpclient.Downloader
+
+	transport *digestTransport
+}
+
+const digestAuthKey contextKey = "pastiche.digest"
+
+// prepareDigest allows the request to be sent again with the response to the
+// challenge by buffering its body and storing the auth in its context
+func prepareDigest(r *http.Request, a *model.DigestAuth) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	*r = *r.WithContext(context.WithValue(r.Context(), digestAuthKey, a))
+	return nil
+}
+
+func (t *digestTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	return t.retry(r, resp)
+}
+
+// retry sends the request again with the Authorization header when the
+// response is the challenge to a request that uses Digest auth. Otherwise,
+// the response is returned as is.
+func (t *digestTransport) retry(r *http.Request, resp *http.Response) (*http.Response, error) {
+	a, ok := r.Context().Value(digestAuthKey).(*model.DigestAuth)
+	if !ok || resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge, err := parseDigestChallenge(resp.Header.Values("WWW-Authenticate"))
+	if err != nil {
+		return resp, nil
+	}
+
+	req := r.Clone(r.Context())
+	var body []byte
+	if r.GetBody != nil {
+		rc, err := r.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		body, err = io.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	authorization, err := digestAuthorization(challenge, a, req.Method, req.URL.RequestURI(), body, newCNonce())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", authorization)
+
+	resp.Body.Close()
+	return t.Base.RoundTrip(req)
+}
+
+// OpenDownload replaces the challenge with the response to the request that
+// was sent again with Digest auth
+func (d *digestDownloader) OpenDownload(ctx context.Context, r *httpclient.Response) (io.WriteCloser, error) {
+	if r.Response != nil && r.Request != nil {
+		resp, err := d.transport.retry(r.Request, r.Response)
+		if err != nil {
+			return nil, fmt.Errorf("digest: %w", err)
+		}
+		r.Response = resp
+	}
+	return d.Downloader.OpenDownload(ctx, r)
+}
+
+func parseDigestChallenge(values []string) (digestChallenge, error) {
+	for _, v := range values {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+
+		res := digestChallenge{}
+		for _, p := range splitDigestParams(params) {
+			name, value, _ := strings.Cut(p, "=")
+			res[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+		return res, nil
+	}
+	return nil, errors.New("digest: server did not provide a Digest challenge")
+}
+
+// splitDigestParams splits the comma-separated parameters, allowing commas
+// within quoted values
+func splitDigestParams(s string) []string {
+	var (
+		res    []string
+		quoted bool
+		start  int
+	)
+	for i, c := range s {
+		switch c {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				res = append(res, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(res, s[start:])
+}
+
+func digestAuthorization(c digestChallenge, a *model.DigestAuth, method, uri string, body []byte, cnonce string) (string, error) {
+	algorithm := c["algorithm"]
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "", "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("digest: unsupported algorithm %q", algorithm)
+	}
+
+	h := func(parts ...string) string {
+		d := newHash()
+		d.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	var (
+		nonce = c["nonce"]
+		nc    = "00000001"
+		qop   string
+	)
+	qops := strings.Split(strings.ReplaceAll(c["qop"], " ", ""), ",")
+	switch {
+	case slices.Contains(qops, "auth"):
+		qop = "auth"
+	case slices.Contains(qops, "auth-int"):
+		qop = "auth-int"
+	}
+
+	ha1 := h(a.User, c["realm"], a.Password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = h(ha1, nonce, cnonce)
+	}
+
+	ha2 := h(method, uri)
+	if qop == "auth-int" {
+		ha2 = h(method, uri, h(string(body)))
+	}
+
+	var response string
+	if qop == "" {
+		response = h(ha1, nonce, ha2)
+	} else {
+		response = h(ha1, nonce, nc, cnonce, qop, ha2)
+	}
+
+	params := []string{
+		fmt.Sprintf("username=%q", a.User),
+		fmt.Sprintf("realm=%q", c["realm"]),
+		fmt.Sprintf("nonce=%q", nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+	if algorithm != "" {
+		params = append(params, "algorithm="+algorithm)
+	}
+	params = append(params, fmt.Sprintf("response=%q", response))
+	if qop != "" {
+		params = append(params, "qop="+qop, "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+	if opaque, ok := c["opaque"]; ok {
+		params = append(params, fmt.Sprintf("opaque=%q", opaque))
+	}
+	return "Digest " + strings.Join(params, ", "), nil
+}
+
+func newCNonce() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DigestAuthorization", func() {
+
+	It("computes the response from RFC 2617", func() {
+		auth, err := phttpclient.DigestAuthorization(
+			`Digest realm="testrealm@host.com", qop="auth,auth-int", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`,
+			&model.DigestAuth{User: "Mufasa", Password: "Circle Of Life"},
+			"GET",
+			"/dir/index.html",
+			"",
+			"0a4f113b",
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth).To(Equal(`Digest username="Mufasa", realm="testrealm@host.com", ` +
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/dir/index.html", ` +
+			`response="6629fae49393a05397450978507c4ef1", qop=auth, nc=00000001, ` +
+			`cnonce="0a4f113b", opaque="5ccc069c403ebaf9f0171e9517f40e41"`))
+	})
+
+	It("returns an error for unsupported algorithms", func() {
+		_, err := phttpclient.DigestAuthorization(
+			`Digest realm="r", nonce="n", algorithm=SHA-512-256`,
+			&model.DigestAuth{User: "u", Password: "p"},
+			"GET", "/", "", "c",
+		)
+		Expect(err).To(MatchError(`digest: unsupported algorithm "SHA-512-256"`))
+	})
+
+	It("authenticates with a digest server", func() {
+		const (
+			realm = "pastiche"
+			nonce = "abc123"
+		)
+		md5Hex := func(s string) string {
+			sum := md5.Sum([]byte(s))
+			return hex.EncodeToString(sum[:])
+		}
+		param := func(header, name string) string {
+			m := regexp.MustCompile(name + `="?([^",]+)"?`).FindStringSubmatch(header)
+			if m == nil {
+				return ""
+			}
+			return m[1]
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := r.Header.Get("Authorization")
+			if strings.HasPrefix(h, "Digest ") {
+				ha1 := md5Hex("u:" + realm + ":p")
+				ha2 := md5Hex(r.Method + ":" + r.URL.RequestURI())
+				expected := md5Hex(strings.Join([]string{
+					ha1, nonce, param(h, "nc"), param(h, "cnonce"), "auth", ha2,
+				}, ":"))
+				if param(h, "response") == expected {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+			}
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm=%q, qop="auth", nonce=%q`, realm, nonce))
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		DeferCleanup(server.Close)
+
+		loc := phttpclient.NewLocation(nil, nil, nil, &model.Endpoint{Method: "GET"}, &model.Request{
+			Auth: &model.DigestAuth{User: "u", Password: "p"},
+		}, nil)
+		req, _ := http.NewRequest("GET", server.URL+"/items?q=1", nil)
+		Expect(loc.Middleware.Handle(req, nil)).To(Succeed())
+
+		client := &http.Client{Transport: phttpclient.NewDigestTransport(http.DefaultTransport)}
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("sends the request once when the server does not challenge", func() {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, r.Method+" "+string(body))
+			w.WriteHeader(http.StatusCreated)
+		}))
+		DeferCleanup(server.Close)
+
+		loc := phttpclient.NewLocation(nil, nil, nil, &model.Endpoint{Method: "POST"}, &model.Request{
+			Auth: &model.DigestAuth{User: "u", Password: "p"},
+			Body: io.NopCloser(strings.NewReader("item")),
+		}, nil)
+		req, _ := http.NewRequest("GET", server.URL+"/items", nil)
+		Expect(loc.Middleware.Handle(req, nil)).To(Succeed())
+
+		client := &http.Client{Transport: phttpclient.NewDigestTransport(http.DefaultTransport)}
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		Expect(requests).To(Equal([]string{"POST item"}))
+	})
+})
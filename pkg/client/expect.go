@@ -0,0 +1,176 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+
+	cli "github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/jmespath/go-jmespath"
+)
+
+// SetExpect provides an action which adds an expectation that the response
+// must satisfy
+func SetExpect(e ...*model.Expectation) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "expect",
+			HelpText: "Require the response to meet an {EXPECTATION}: status=CODE, header=NAME:PATTERN, jmespath=QUERY, xpath=QUERY, or latency=DURATION",
+			Value:    new(model.Expectation),
+			Options:  cli.EachOccurrence,
+		},
+		withBinding((*Client).AddExpectation, e),
+	)
+}
+
+// AddExpectation adds an expectation that the response must satisfy
+func (c *Client) AddExpectation(e *model.Expectation) error {
+	c.expectations = append(c.expectations, e)
+	return nil
+}
+
+// checkExpectations verifies the expectations from the command line and
+// the endpoint against the last response that was received
+func (c *Client) checkExpectations(ctx context.Context, start time.Time) error {
+	expectations := slices.Clone(c.expectations)
+	if sr, ok := c.locationResolver.(*serviceResolver); ok {
+		if resolved, err := sr.resolveResource(ctx); err == nil && resolved.Endpoint() != nil {
+			if e := resolved.Endpoint().Expect; e != nil {
+				expectations = append(expectations, e)
+			}
+		}
+	}
+	if len(expectations) == 0 {
+		return nil
+	}
+
+	h := c.lastHistory
+	if h == nil {
+		return fmt.Errorf("no response was available to check expectations")
+	}
+
+	latency := h.Timestamp.Sub(start)
+	var errs []error
+	for _, e := range expectations {
+		errs = append(errs, verifyExpectation(ctx, e, h, latency))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("expectation failed: %w", err)
+	}
+	return nil
+}
+
+func verifyExpectation(_ context.Context, e *model.Expectation, h *history, latency time.Duration) error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if len(e.Status) > 0 && !slices.Contains(e.Status, h.Response.StatusCode) {
+		fail("status %d not in %v", h.Response.StatusCode, e.Status)
+	}
+
+	headers := http.Header(h.Response.Headers)
+	for _, name := range slices.Sorted(maps.Keys(e.Headers)) {
+		values := headers.Values(name)
+		for _, pattern := range e.Headers[name] {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !slices.ContainsFunc(values, re.MatchString) {
+				fail("header %s does not match %q", name, pattern)
+			}
+		}
+	}
+
+	if len(e.JMESPath) > 0 || len(e.XPath) > 0 {
+		resp := newResponse(h.Response.Body.buffer.Bytes(), headers.Get("Content-Type"), nil)
+
+		for _, q := range e.JMESPath {
+			ok, err := jmesPathPredicate(resp, q)
+			if err != nil {
+				errs = append(errs, err)
+			} else if !ok {
+				fail("jmespath %q is not satisfied", q)
+			}
+		}
+
+		for _, q := range e.XPath {
+			ok, err := xpathPredicate(resp, q)
+			if err != nil {
+				errs = append(errs, err)
+			} else if !ok {
+				fail("xpath %q is not satisfied", q)
+			}
+		}
+	}
+
+	if e.MaxLatency > 0 && latency > e.MaxLatency {
+		fail("latency %s exceeds %s", latency, e.MaxLatency)
+	}
+
+	return errors.Join(errs...)
+}
+
+func jmesPathPredicate(resp Response, query string) (bool, error) {
+	data, err := resp.Data()
+	if err != nil {
+		return false, err
+	}
+	res, err := jmespath.Search(query, data)
+	if err != nil {
+		return false, err
+	}
+
+	// Use JMESPath semantics for truthiness
+	switch v := res.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		return v != "", nil
+	case []any:
+		return len(v) > 0, nil
+	case map[string]any:
+		return len(v) > 0, nil
+	}
+	return true, nil
+}
+
+func xpathPredicate(resp Response, query string) (bool, error) {
+	doc, err := resp.Document()
+	if err != nil {
+		return false, err
+	}
+	q, err := xpath.Compile(query)
+	if err != nil {
+		return false, err
+	}
+
+	switch v := q.Evaluate(xmlquery.CreateXPathNavigator(doc.(*xmlquery.Node))).(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		return v != "", nil
+	case *xpath.NodeIterator:
+		return v.MoveNext(), nil
+	}
+	return false, nil
+}
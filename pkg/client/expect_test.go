@@ -0,0 +1,95 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+	"io"
+	"time"
+
+	cli "github.com/Carbonfrost/joe-cli"
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("Expectation", func() {
+
+	var (
+		jsonHeaders = map[string][]string{"Content-Type": {"application/json"}}
+		xmlHeaders  = map[string][]string{"Content-Type": {"application/xml"}}
+	)
+
+	DescribeTable("examples",
+		func(e *model.Expectation, status int, headers map[string][]string, body string, expected types.GomegaMatcher) {
+			err := phttpclient.VerifyExpectation(e, status, headers, body, 100*time.Millisecond)
+			Expect(err).To(expected)
+		},
+		Entry("status",
+			&model.Expectation{Status: []int{200, 201}}, 201, jsonHeaders, `{}`,
+			Succeed()),
+		Entry("status mismatch",
+			&model.Expectation{Status: []int{200}}, 500, jsonHeaders, `{}`,
+			MatchError("status 500 not in [200]")),
+		Entry("header",
+			&model.Expectation{Headers: map[string][]string{"Content-Type": {"^application/"}}}, 200, jsonHeaders, `{}`,
+			Succeed()),
+		Entry("header mismatch",
+			&model.Expectation{Headers: map[string][]string{"Content-Type": {"xml$"}}}, 200, jsonHeaders, `{}`,
+			MatchError(`header Content-Type does not match "xml$"`)),
+		Entry("missing header",
+			&model.Expectation{Headers: map[string][]string{"ETag": {"."}}}, 200, jsonHeaders, `{}`,
+			MatchError(`header ETag does not match "."`)),
+		Entry("JMESPath",
+			&model.Expectation{JMESPath: []string{"length(items) > `1`"}}, 200, jsonHeaders, `{"items": [1, 2]}`,
+			Succeed()),
+		Entry("JMESPath not satisfied",
+			&model.Expectation{JMESPath: []string{"items"}}, 200, jsonHeaders, `{"items": []}`,
+			MatchError("jmespath \"items\" is not satisfied")),
+		Entry("XPath",
+			&model.Expectation{XPath: []string{"//item[@id='a']"}}, 200, xmlHeaders, `<items><item id="a"/></items>`,
+			Succeed()),
+		Entry("XPath boolean",
+			&model.Expectation{XPath: []string{"count(//item) > 1"}}, 200, xmlHeaders, `<items><item id="a"/></items>`,
+			MatchError(`xpath "count(//item) > 1" is not satisfied`)),
+		Entry("latency",
+			&model.Expectation{MaxLatency: time.Second}, 200, jsonHeaders, `{}`,
+			Succeed()),
+		Entry("latency exceeded",
+			&model.Expectation{MaxLatency: 50 * time.Millisecond}, 200, jsonHeaders, `{}`,
+			MatchError("latency 100ms exceeds 50ms")),
+	)
+
+	It("reports every failure", func() {
+		err := phttpclient.VerifyExpectation(&model.Expectation{
+			Status:   []int{200},
+			JMESPath: []string{"ok"},
+		}, 404, jsonHeaders, `{"ok": false}`, 0)
+
+		Expect(err).To(MatchError("status 404 not in [200]\njmespath \"ok\" is not satisfied"))
+	})
+
+	It("adds an expectation for each occurrence of the flag", func() {
+		c := phttpclient.New()
+		app := &cli.App{
+			Name:   "app",
+			Stdout: io.Discard,
+			Uses:   phttpclient.ContextValue(c),
+			Flags: []*cli.Flag{
+				{Uses: phttpclient.SetExpect()},
+			},
+		}
+
+		args, _ := cli.Split("app --expect status=200 --expect latency=1s")
+		Expect(app.RunContext(context.Background(), args)).To(Succeed())
+		Expect(phttpclient.Expectations(c)).To(Equal([]*model.Expectation{
+			{Status: []int{200}},
+			{MaxLatency: time.Second},
+		}))
+	})
+})
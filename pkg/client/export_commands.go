@@ -0,0 +1,108 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli/extensions/bind"
+	"github.com/Carbonfrost/pastiche/pkg/collection"
+	"github.com/Carbonfrost/pastiche/pkg/contextual"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/Carbonfrost/pastiche/pkg/openapi"
+	"sigs.k8s.io/yaml"
+)
+
+type ExportParams struct {
+	Format  string
+	Service string
+}
+
+// exporter converts the service into another format
+type exporter func(svc *model.Service) ([]byte, error)
+
+var exporters = map[string]exporter{
+	"http":    exportHTTPFile,
+	"openapi": exportOpenAPI,
+	"postman": exportPostman,
+}
+
+// Export provides the action for exporting a service to another format
+func Export() cli.Action {
+	return cli.Pipeline(
+		cli.Prototype{
+			HelpText: "Export a service definition from the Pastiche workspace to another format",
+		},
+		bind.Call2(exportSpec, bind.Context(), useExportParams()),
+	)
+}
+
+func exportSpec(c *cli.Context, params *ExportParams) error {
+	exp, ok := exporters[params.Format]
+	if !ok {
+		return fmt.Errorf("unknown export format %q", params.Format)
+	}
+
+	svc, ok := contextual.Workspace(c).Model().Service(params.Service)
+	if !ok {
+		return fmt.Errorf("service not found: %q", params.Service)
+	}
+
+	data, err := exp(svc)
+	if err != nil {
+		return err
+	}
+	_, err = c.Stdout.Write(data)
+	return err
+}
+
+func exportOpenAPI(svc *model.Service) ([]byte, error) {
+	return yaml.Marshal(openapi.FromModel(svc))
+}
+
+func exportHTTPFile(svc *model.Service) ([]byte, error) {
+	var buf bytes.Buffer
+	err := model.WriteHTTPFile(&buf, svc)
+	return buf.Bytes(), err
+}
+
+func exportPostman(svc *model.Service) ([]byte, error) {
+	data, err := json.MarshalIndent(collection.PostmanFromModel(svc), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func useExportParams() bind.ActionBinder[*ExportParams] {
+	return newParams(cli.Pipeline(
+		cli.Setup{
+			Uses: cli.AddArgs([]*cli.Arg{
+				{
+					Name:       "format",
+					Value:      new(string),
+					Completion: cli.ValueCompletion(slices.Sorted(maps.Keys(exporters))...),
+				},
+				{
+					Name:       "service",
+					Value:      new(string),
+					Completion: completeServices(),
+				},
+			}...),
+		},
+	),
+		func(c *cli.Context) (*ExportParams, error) {
+			return &ExportParams{
+				Format:  c.String("format"),
+				Service: c.String("service"),
+			}, nil
+		},
+	)
+}
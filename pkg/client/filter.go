@@ -421,25 +421,32 @@ func (f *filteredDownload) OpenDownload(ctx context.Context, r *joehttpclient.Re
 }
 
 func (c *filteredWriter) parseResponse(data []byte) (Response, error) {
-	ct := c.contentType
+	// Metadata of responses without content is provided as JSON regardless
+	// of the content type, such as in the response to HEAD
+	if len(data) == 0 {
+		return &jsonResponse{data, c.history}, nil
+	}
+	return newResponse(data, c.contentType, c.history), nil
+}
 
+func newResponse(data []byte, ct string, h *history) Response {
 	switch {
 	case strings.HasPrefix(ct, "application/x-www-form-urlencoded"),
 		strings.HasPrefix(ct, "multipart/form-data"):
 		// TODO: Support form parsing and responses
-		return &rawResponse{data}, nil
+		return &rawResponse{data}
 
 	case strings.HasPrefix(ct, "application/xml"),
 		strings.HasPrefix(ct, "text/xml"):
-		return &xmlResponse{data}, nil
+		return &xmlResponse{data}
 
 	case strings.HasPrefix(ct, "application/json"),
 		strings.HasPrefix(ct, "text/json"),
 		ct == "":
-		return &jsonResponse{data, c.history}, nil
+		return &jsonResponse{data, h}
 
 	default:
-		return &rawResponse{data}, nil
+		return &rawResponse{data}
 	}
 }
 
@@ -448,6 +455,12 @@ func (c *filteredWriter) Close() error {
 		defer closer.Close()
 	}
 
+	// There is nothing to filter when the response has no content unless
+	// its metadata is included
+	if c.Buffer.Len() == 0 && c.history == nil {
+		return nil
+	}
+
 	resp, err := c.parseResponse(c.Buffer.Bytes())
 	if err != nil {
 		return err
@@ -45,6 +45,48 @@ var _ = Describe("FilterDownloader", func() {
 		})
 	})
 
+	Context("when the response has no content", func() {
+
+		It("writes nothing", func() {
+			testResponse := &joehttpclient.Response{
+				Response: &http.Response{
+					StatusCode: 204,
+					Body:       io.NopCloser(new(bytes.Buffer)),
+				},
+			}
+
+			var buf bytes.Buffer
+			d := client.NewFilterDownloader(nil, joehttpclient.NewDownloaderTo(&buf), nil)
+
+			writer, _ := d.OpenDownload(context.Background(), testResponse)
+			_ = testResponse.CopyTo(writer)
+			Expect(writer.Close()).To(Succeed())
+			Expect(buf.String()).To(BeEmpty())
+		})
+
+		It("writes the metadata when it is included", func() {
+			testResponse := &joehttpclient.Response{
+				Response: &http.Response{
+					StatusCode: 204,
+					Header:     http.Header{"Content-Type": {"text/html"}},
+					Body:       io.NopCloser(new(bytes.Buffer)),
+				},
+			}
+
+			var buf bytes.Buffer
+			d := client.NewMetadataFilterDownloader(nil, joehttpclient.NewDownloaderTo(&buf), 204)
+
+			writer, _ := d.OpenDownload(context.Background(), testResponse)
+			_ = testResponse.CopyTo(writer)
+			Expect(writer.Close()).To(Succeed())
+
+			var actual map[string]any
+			Expect(json.Unmarshal(buf.Bytes(), &actual)).To(Succeed())
+			Expect(actual).To(HaveKeyWithValue("$meta", HaveKeyWithValue("response", HaveKeyWithValue("statusCode", 204.0))))
+			Expect(actual).To(HaveKeyWithValue("result", BeNil()))
+		})
+	})
+
 	Context("when DigFilter", func() {
 
 		It("writes to the output of inner downloader", func() {
@@ -132,6 +174,38 @@ var _ = Describe("FilterDownloader", func() {
 		})
 	})
 
+	Context("when the response has no content", func() {
+
+		It("writes nothing to the output", func() {
+			testResponse := &joehttpclient.Response{
+				Response: &http.Response{
+					Header: http.Header{
+						"Content-Type": []string{"application/json"},
+					},
+					Body: io.NopCloser(bytes.NewBufferString("")),
+				},
+			}
+
+			var buf bytes.Buffer
+			d := client.NewFilterDownloader(
+				must(client.NewJMESPathFilter("a")),
+				joehttpclient.NewDownloaderTo(&buf),
+				nil,
+			)
+
+			writer, err := d.OpenDownload(context.Background(), testResponse)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = testResponse.CopyTo(writer)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = writer.Close()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(buf.String()).To(BeEmpty())
+		})
+	})
+
 	Context("when RawFilter", func() {
 
 		It("outputs raw data without processing", func() {
@@ -0,0 +1,167 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli/extensions/bind"
+	"github.com/Carbonfrost/pastiche/pkg/contextual"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+type RunParams struct {
+	Flow string
+}
+
+// Run provides the action for running the steps of a flow
+func Run() cli.Action {
+	return cli.Pipeline(
+		cli.Prototype{
+			HelpText: "Run the steps of a flow defined in the Pastiche workspace",
+		},
+		New(
+			WithFlowLocationResolver(),
+		),
+		bind.Call2(runFlow, bind.Context(), useRunParams()),
+	)
+}
+
+func runFlow(c *cli.Context, params *RunParams) error {
+	mo := contextual.Workspace(c).Model()
+	flow, ok := mo.Flow(params.Flow)
+	if !ok {
+		return fmt.Errorf("flow not found: %q", params.Flow)
+	}
+
+	client := FromContext(c)
+	sr := client.locationResolver.(*serviceResolver)
+	defer sr.setStep(nil, nil)
+
+	for _, step := range flow.Steps {
+		name := stepName(step)
+
+		var (
+			status     string
+			captureErr error
+		)
+		client.onHistory = func(h *history) {
+			status = h.Response.Status
+			captureErr = capture(c, sr, h, step.Captures)
+		}
+
+		sr.setStep(flow, step)
+		err := client.fetchAndPrint(c)
+		if err == nil {
+			err = captureErr
+		}
+		if err != nil {
+			fmt.Fprintf(c.Stderr, "%s: failed\n", name)
+			return fmt.Errorf("step %q: %w", name, err)
+		}
+		fmt.Fprintf(c.Stderr, "%s: %s\n", name, cmp.Or(status, "done"))
+	}
+	return nil
+}
+
+// capture applies the captures of the step to the response in the history
+// and stores the results as vars so that they are available to later steps
+func capture(ctx context.Context, sr *serviceResolver, h *history, captures []*model.Capture) error {
+	if len(captures) == 0 {
+		return nil
+	}
+
+	resp := newResponse(
+		h.Response.Body.buffer.Bytes(),
+		http.Header(h.Response.Headers).Get("Content-Type"),
+		nil,
+	)
+	for _, c := range captures {
+		f, err := outputFilterToFilter(c.Filter)
+		if err != nil {
+			return fmt.Errorf("capture %q: %w", c.Name, err)
+		}
+
+		data, err := f.Search(ctx, resp)
+		if err != nil {
+			return fmt.Errorf("capture %q: %w", c.Name, err)
+		}
+
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			value = string(data)
+		}
+		if err := sr.AddVar(c.Name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stepName(step *model.Step) string {
+	var target string
+	switch t := step.StepType.(type) {
+	case *model.SpecStep:
+		target = t.Spec
+	case *model.URLStep:
+		target = t.URL
+	}
+	return cmp.Or(step.Name, step.Title, target)
+}
+
+func useRunParams() bind.ActionBinder[*RunParams] {
+	return newParams(cli.Pipeline(
+		cli.Setup{
+			Uses: cli.Pipeline(
+				cli.AddArgs([]*cli.Arg{
+					{
+						Name:       "flow",
+						Value:      new(string),
+						Completion: completeFlows(),
+					},
+					{
+						Name:    "args",
+						Value:   new(cli.NameValue),
+						Options: cli.EachOccurrence,
+						NArg:    cli.TakeUntilNextFlag,
+						Action:  addVarArg,
+					},
+				}...,
+				),
+				// The server is bound late by the location resolver
+				cli.AddFlags([]*cli.Flag{
+					{
+						Name:     "server",
+						Aliases:  []string{"S"},
+						HelpText: "Use the specified server for the requests",
+						Value:    new(string),
+					},
+				}...),
+			),
+		},
+	),
+		func(c *cli.Context) (*RunParams, error) {
+			return &RunParams{
+				Flow: c.String("flow"),
+			}, nil
+		},
+	)
+}
+
+func completeFlows() cli.CompletionFunc {
+	return func(cc *cli.Context) []cli.CompletionItem {
+		mo := contextual.Workspace(cc).Model()
+		names := make([]string, 0, len(mo.Flows))
+		for _, f := range mo.Flows {
+			names = append(names, f.Name)
+		}
+		return cli.ValueCompletion(names...).Complete(cc)
+	}
+}
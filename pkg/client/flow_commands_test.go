@@ -0,0 +1,58 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"context"
+
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capture", func() {
+
+	var newResolver = func() phttpclient.LocationResolver {
+		return phttpclient.NewServiceResolver(
+			func(context.Context) *model.Model { return &model.Model{} },
+			func(context.Context) *model.ServiceSpec { return new(model.ServiceSpec) },
+			func(context.Context) string { return "" },
+			func(context.Context) string { return "" },
+		)
+	}
+
+	DescribeTable("examples", func(body string, c *model.Capture, expected any) {
+		r := newResolver()
+		err := phttpclient.Capture(r, nil, body, c)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Vars()).To(HaveKeyWithValue(c.Name, expected))
+	},
+		Entry("JMESPath",
+			`{"id": 42, "name": "a"}`,
+			&model.Capture{Name: "id", Filter: &model.JMESPathOutput{Query: "id"}},
+			float64(42),
+		),
+		Entry("dig",
+			`{"data": {"token": "t0k3n"}}`,
+			&model.Capture{Name: "token", Filter: &model.DigOutput{Query: "data.token"}},
+			"t0k3n",
+		),
+		Entry("structured",
+			`{"items": [{"id": "a"}, {"id": "b"}]}`,
+			&model.Capture{Name: "ids", Filter: &model.JMESPathOutput{Query: "items[].id"}},
+			[]any{"a", "b"},
+		),
+	)
+
+	It("returns an error when the response cannot be queried", func() {
+		r := newResolver()
+		err := phttpclient.Capture(r, map[string][]string{
+			"Content-Type": {"application/xml"},
+		}, "<a/>", &model.Capture{Name: "id", Filter: &model.JMESPathOutput{Query: "id"}})
+		Expect(err).To(MatchError(ContainSubstring(`capture "id"`)))
+	})
+})
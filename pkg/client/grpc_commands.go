@@ -0,0 +1,143 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/Carbonfrost/joe-cli/extensions/bind"
+	"github.com/Carbonfrost/pastiche/pkg/grpcclient"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+type GRPCParams struct {
+	Service *model.ServiceSpec
+	Symbol  string
+}
+
+// GRPCList provides the action for listing the services of a gRPC server
+func GRPCList() cli.Action {
+	return cli.Pipeline(
+		cli.Prototype{
+			HelpText: "List the gRPC services provided by the server of a service",
+		},
+		New(
+			WithDefaultLocationResolver(),
+		),
+		bind.Call2(grpcList, bind.Context(), useGRPCParams(false)),
+	)
+}
+
+// GRPCDescribe provides the action for describing a symbol provided by a gRPC
+// server, such as a service, method, or message
+func GRPCDescribe() cli.Action {
+	return cli.Pipeline(
+		cli.Prototype{
+			HelpText: "Describe a gRPC symbol provided by the server of a service",
+		},
+		New(
+			WithDefaultLocationResolver(),
+		),
+		bind.Call2(grpcDescribe, bind.Context(), useGRPCParams(true)),
+	)
+}
+
+func grpcList(c *cli.Context, _ *GRPCParams) error {
+	if err := httpClientInterop(c); err != nil {
+		return err
+	}
+
+	services, err := grpcclient.FromContext(c).ListServices(c)
+	if err != nil {
+		return err
+	}
+	return FromContext(c).print(c, services)
+}
+
+func grpcDescribe(c *cli.Context, params *GRPCParams) error {
+	if err := httpClientInterop(c); err != nil {
+		return err
+	}
+
+	d, err := grpcclient.FromContext(c).Describe(c, params.Symbol)
+	if err != nil {
+		return err
+	}
+	return FromContext(c).print(c, d)
+}
+
+// print writes the value as JSON using the filter of the client
+func (c *Client) print(ctx *cli.Context, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	resp := &httpclient.Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(data)),
+		},
+	}
+
+	d := NewFilterDownloader(c.filter, httpclient.NewDownloaderTo(ctx.Stdout), nil)
+	w, err := d.OpenDownload(ctx, resp)
+	if err != nil {
+		return err
+	}
+	if err := resp.CopyTo(w); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func useGRPCParams(symbol bool) bind.ActionBinder[*GRPCParams] {
+	args := []*cli.Arg{
+		{
+			Name:       "service",
+			Value:      new(model.ServiceSpec),
+			NArg:       1,
+			Completion: completeServices(),
+		},
+	}
+	if symbol {
+		args = append(args, &cli.Arg{
+			Name:     "symbol",
+			HelpText: "The fully-qualified name of the {SYMBOL}",
+			Value:    new(string),
+		})
+	}
+
+	return newParams(cli.Pipeline(
+		cli.Setup{
+			Uses: cli.Pipeline(
+				cli.AddArgs(args...),
+				cli.AddFlags([]*cli.Flag{
+					{
+						Name:       "server",
+						Aliases:    []string{"S"},
+						HelpText:   "Use the specified server for the request",
+						Value:      new(string),
+						Completion: completeServer(),
+					},
+				}...),
+			),
+		},
+	),
+		func(c *cli.Context) (*GRPCParams, error) {
+			return &GRPCParams{
+				Service: c.Value("service").(*model.ServiceSpec),
+				Symbol:  c.String("symbol"),
+			}, nil
+		},
+	)
+}
@@ -8,8 +8,9 @@ import (
 	"cmp"
 	"context"
 	"fmt"
-	"io"
+	"maps"
 	"slices"
+	"time"
 
 	"github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
@@ -27,9 +28,10 @@ const (
 )
 
 type Request struct {
-	Spec   *model.ServiceSpec
-	Method string
-	Server string
+	Spec    *model.ServiceSpec
+	Method  string
+	Server  string
+	VarSets []string
 }
 
 type DescribeParams struct {
@@ -37,13 +39,6 @@ type DescribeParams struct {
 	Tags []string
 }
 
-type ImportParams struct {
-	*Request
-	Name        string
-	Title       string
-	Description string
-}
-
 func newParams[T any](action cli.Action, binder bind.Func[T]) bind.ActionBinder[T] {
 	return bind.NewActionBinder(action, binder)
 }
@@ -107,11 +102,31 @@ func describeSpec(c *cli.Context, params *DescribeParams) error {
 		return true
 	}
 
+	// Display the vars of the selected var sets as part of the service
+	withVarSets := func(r model.ResolvedResource, service *model.Service) (*model.Service, error) {
+		vars, err := mo.SelectVarSets(r, req.VarSets...)
+		if err != nil || len(vars) == 0 {
+			return service, err
+		}
+
+		res := *service
+		res.Vars = maps.Clone(service.Vars)
+		if res.Vars == nil {
+			res.Vars = map[string]any{}
+		}
+		maps.Copy(res.Vars, vars)
+		return &res, nil
+	}
+
 	// When no spec, consider all services, optionally filtering on tags
 	if req.Spec == nil || len(*req.Spec) == 0 {
 		var matchingServices []*model.Service
 		for _, service := range mo.Services {
 			if matchesAllTags(service.Tags) {
+				service, err := withVarSets(nil, service)
+				if err != nil {
+					return err
+				}
 				matchingServices = append(matchingServices, service)
 			}
 		}
@@ -126,9 +141,13 @@ func describeSpec(c *cli.Context, params *DescribeParams) error {
 	}
 
 	if matchesAllTags(merged.Service().Tags) {
+		service, err := withVarSets(merged, merged.Service())
+		if err != nil {
+			return err
+		}
 		return displayService(&model.Model{
 			Services: []*model.Service{
-				merged.Service(),
+				service,
 			},
 		})
 	} else {
@@ -153,6 +172,7 @@ func useDescribeParams() bind.ActionBinder[*DescribeParams] {
 				Aliases:  []string{"t"},
 				Value:    new([]string),
 			},
+			{Uses: SetVarSets()},
 		}...),
 	),
 		func(c *cli.Context) (*DescribeParams, error) {
@@ -165,37 +185,6 @@ func useDescribeParams() bind.ActionBinder[*DescribeParams] {
 	)
 }
 
-func useImportParams() bind.ActionBinder[*ImportParams] {
-	requestBinder := useRequest()
-	return newParams(cli.Pipeline(
-		requestBinder,
-		cli.AddFlags([]*cli.Flag{
-			{
-				Name:     "name",
-				HelpText: "Set the name of the resource",
-			},
-			{
-				Name:     "title",
-				HelpText: "Set the title of the resource",
-			},
-			{
-				Name:     "description",
-				HelpText: "Set the description of the resource",
-			},
-		}...),
-	),
-		func(c *cli.Context) (*ImportParams, error) {
-			r, err := requestBinder.Bind(c)
-			return &ImportParams{
-				Request:     r,
-				Name:        c.String("name"),
-				Title:       c.String("title"),
-				Description: c.String("description"),
-			}, err
-		},
-	)
-}
-
 // Open reveals a particular file or link in the editor or web browser
 func Open() cli.Action {
 	return cli.Pipeline(
@@ -212,19 +201,24 @@ func Open() cli.Action {
 			// Allow params to be used to fill template variables
 			{Uses: httpclient.SetURITemplateVar()},
 			{Uses: httpclient.SetURITemplateVars()},
+			{Uses: SetVarSets()},
 		}...),
 		bind.Action2(openSpec, useRequest(), bind.String("rel")),
 	)
 }
 
-// Import provides the action for importing a definition
-func Import() cli.Action {
-	return cli.Pipeline(
-		cli.Prototype{
-			Description: "Import a service configuration from another format",
-		},
-		bind.Call2(importSpec, bind.Context(), useImportParams()),
-	)
+// SetVarSets provides an action which selects var sets to apply to the request.
+// A var set is selected as SET.NAME or NAME, where NAME is an entry within the
+// var set
+func SetVarSets() cli.Action {
+	return &cli.Prototype{
+		Name:       "varset",
+		Aliases:    []string{"V"},
+		HelpText:   "Apply the vars from the var set selected by {NAME}",
+		Value:      new([]string),
+		Category:   requestOptions,
+		Completion: completeVarSets(),
+	}
 }
 
 func SetVarFromEnvVar(v ...*uritemplates.Var) cli.Action {
@@ -248,33 +242,82 @@ func FetchAndPrint() cli.Action {
 			return fmt.Errorf("required argument URL missing")
 		}
 
-		c := FromContext(ctx)
+		return FromContext(ctx).fetchAndPrint(ctx)
+	})
+}
 
-		clientType := c.Type()
-		// TODO This should delegate to respective location methods rather than
-		// have them resolve this again within. This also assumes only one location
-		// is ever returned
+func (c *Client) fetchAndPrint(ctx context.Context) error {
+	clientType := c.Type()
+	// TODO This should delegate to respective location methods rather than
+	// have them resolve this again within. This also assumes only one location
+	// is ever returned
 
-		if clientType == TypeUnspecified {
-			locations, err := c.locationResolver.Resolve(ctx)
-			if err != nil {
-				return err
-			}
-			if p, ok := locations[0].(Location); ok {
-				clientType = fromClientType(p.Resolved().Client())
-			}
+	if clientType == TypeUnspecified {
+		locations, err := c.locationResolver.Resolve(ctx)
+		if err != nil {
+			return err
 		}
-
-		if clientType == TypeGRPC {
-			return cli.Do(ctx, cli.Pipeline(httpClientInterop, grpcclient.FetchAndPrint()))
+		if p, ok := locations[0].(Location); ok {
+			clientType = fromClientType(p.Resolved().Client())
 		}
+	}
 
-		return cli.Do(ctx, httpclient.FetchAndPrint())
-	})
+	c.lastHistory = nil
+	start := time.Now()
+
+	var err error
+	switch clientType {
+	case TypeGRPC:
+		err = cli.Do(ctx, cli.Pipeline(httpClientInterop, grpcclient.FetchAndPrint()))
+	case TypeGRPCWeb, TypeConnect:
+		err = c.fetchAndPrintWeb(ctx, clientType)
+	default:
+		err = cli.Do(ctx, httpclient.FetchAndPrint())
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.checkExpectations(ctx, start)
+}
+
+// fetchAndPrintWeb calls a gRPC-Web or Connect service using the HTTP client.
+// The codec from the gRPC client converts the request and response, which
+// otherwise are handled like any HTTP request and response.
+func (c *Client) fetchAndPrintWeb(ctx context.Context, clientType Type) error {
+	sr, ok := c.locationResolver.(*serviceResolver)
+	if !ok {
+		return fmt.Errorf("%s client requires a service", clientType)
+	}
+
+	locations, err := c.locationResolver.Resolve(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := locations[0].(Location); !ok {
+		return fmt.Errorf("%s client requires a service rather than a URL", clientType)
+	}
+
+	codec, err := c.grpc.WebCodec(ctx, webProtocols[clientType], locations[0])
+	if err != nil {
+		return err
+	}
+
+	sr.web = codec
+	defer func() {
+		sr.web = nil
+	}()
+	return cli.Do(ctx, httpclient.FetchAndPrint())
 }
 
 func fromClientType(c model.Client) Type {
-	if _, ok := c.(*model.GRPCClient); ok {
+	if g, ok := c.(*model.GRPCClient); ok {
+		switch g.Protocol {
+		case grpcclient.ProtocolGRPCWeb:
+			return TypeGRPCWeb
+		case grpcclient.ProtocolConnect:
+			return TypeConnect
+		}
 		return TypeGRPC
 	}
 	if _, ok := c.(*model.HTTPClient); ok {
@@ -310,67 +353,12 @@ func resolveRequest(c context.Context, req *Request) (*model.Request, error) {
 		return nil, err
 	}
 
-	return merged.EvalRequest(sr.BaseURL(), sr.Vars())
-}
-
-func importSpec(c *cli.Context, params *ImportParams) error {
-	in, err := io.ReadAll(c.Stdin)
-	if err != nil {
-		return err
-	}
-
-	call, err := model.ParseJSFetchCall(string(in))
+	vars, err := selectVars(mo, merged, req.VarSets, sr.Vars())
 	if err != nil {
-		return err
-	}
-	if call.Options.Method == "" {
-		call.Options.Method = "GET"
-	}
-
-	endpoint := call.ToEndpoint()
-	endpoint.Name = params.Name
-	endpoint.Description = params.Description
-	endpoint.Title = params.Title
-
-	ss := *params.Spec
-	var servers []*model.Server
-	if params.Server != "" {
-		servers = append(servers, &model.Server{
-			Name: params.Server,
-		})
-	}
-	if params.Method != "" {
-		endpoint.Method = params.Method
-	}
-
-	mo := &model.Model{
-		Services: []*model.Service{
-			{
-				Name:     ss[0],
-				Resource: &model.Resource{},
-				Servers:  servers,
-			},
-		},
-	}
-
-	current := mo.Services[0].Resource
-	for _, s := range ss[1:] {
-		newChild := &model.Resource{
-			Name: s,
-		}
-		current.Resources = append(current.Resources, newChild)
-		current = newChild
-	}
-	current.Endpoints = append(current.Endpoints, endpoint)
-
-	// TODO Actually persist in the config location specified
-	data, err := yaml.Marshal(model.ToConfig(mo))
-	if err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Println(string(data))
 
-	return nil
+	return merged.EvalRequest(sr.BaseURL(), vars)
 }
 
 func useRequest() bind.ActionBinder[*Request] {
@@ -390,11 +378,7 @@ func useRequest() bind.ActionBinder[*Request] {
 						Options:    cli.EachOccurrence,
 						Completion: completeServiceArgs(),
 						NArg:       cli.TakeUntilNextFlag,
-						Action: func(c *cli.Context) error {
-							it := c.NameValue("")
-							httpclient.FromContext(c).LocationResolver.AddVar(it.Name, it.Value)
-							return nil
-						},
+						Action:     addVarArg,
 					},
 				}...,
 				),
@@ -412,14 +396,22 @@ func useRequest() bind.ActionBinder[*Request] {
 	),
 		func(c *cli.Context) (*Request, error) {
 			return &Request{
-				Spec:   c.Value("service").(*model.ServiceSpec),
-				Method: c.String("method"),
-				Server: c.String("server"),
+				Spec:    c.Value("service").(*model.ServiceSpec),
+				Method:  c.String("method"),
+				Server:  c.String("server"),
+				VarSets: c.List("varset"),
 			}, nil
 		},
 	)
 }
 
+// addVarArg adds the name-value pair from the args argument as a variable
+// to the location resolver
+func addVarArg(c *cli.Context) error {
+	it := c.NameValue("")
+	return httpclient.FromContext(c).LocationResolver.AddVar(it.Name, it.Value)
+}
+
 func completeServices() cli.CompletionFunc {
 	return func(cc *cli.Context) []cli.CompletionItem {
 		mo := contextual.Workspace(cc).Model()
@@ -443,6 +435,27 @@ func completeServiceArgs() cli.CompletionFunc {
 	}
 }
 
+func completeVarSets() cli.CompletionFunc {
+	return func(cc *cli.Context) []cli.CompletionItem {
+		mo := contextual.Workspace(cc).Model()
+
+		var resolved model.ResolvedResource
+		if v, ok := cc.Value("service").(*model.ServiceSpec); ok && len(*v) > 0 {
+			resolved, _ = mo.Resolve(*v, cc.String("server"), cc.String("method"))
+		}
+
+		var names []string
+		for _, s := range mo.ResolveVarSets(resolved) {
+			names = append(names, s.Selections()...)
+			for name := range s.Vars {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		return cli.ValueCompletion(slices.Compact(names)...).Complete(cc)
+	}
+}
+
 func completeServer() cli.CompletionFunc {
 	return func(cc *cli.Context) []cli.CompletionItem {
 		service, _, ok := tryContextResolve(cc)
@@ -0,0 +1,366 @@
+// Copyright 2025, 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli-http/uritemplates"
+	"github.com/Carbonfrost/joe-cli/extensions/bind"
+	"github.com/Carbonfrost/pastiche/pkg/collection"
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/contextual"
+	"github.com/Carbonfrost/pastiche/pkg/grpcclient"
+	"github.com/Carbonfrost/pastiche/pkg/internal/log"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/Carbonfrost/pastiche/pkg/openapi"
+	"sigs.k8s.io/yaml"
+)
+
+type ImportParams struct {
+	*Request
+	Name        string
+	Title       string
+	Description string
+	From        string
+	ProtoSet    string
+	Write       bool
+}
+
+// importer converts the input into the service named by the params
+type importer func(data []byte, params *ImportParams) (*config.Service, error)
+
+// importFormat provides the importer for a format and detects whether
+// the input is in that format
+type importFormat struct {
+	detect func(data []byte) bool
+	imp    importer
+}
+
+var importFormats = map[string]importFormat{
+	"curl":                {hasPrefix("curl "), importCurl},
+	"fetch":               {hasPrefix("fetch("), importFetch},
+	"grpc":                {grpcclient.IsProtoSet, importGRPC},
+	"har":                 {collection.IsHAR, importHAR},
+	"insomnia":            {collection.IsInsomnia, importInsomnia},
+	"openapi":             {isOpenAPI, importOpenAPI},
+	"postman":             {collection.IsPostman, importPostman},
+	"postman-environment": {collection.IsPostmanEnvironment, importPostmanEnvironment},
+}
+
+// Import provides the action for importing a definition
+func Import() cli.Action {
+	return cli.Pipeline(
+		cli.Prototype{
+			Description: "Import a service configuration from another format",
+		},
+		bind.Call2(importSpec, bind.Context(), useImportParams()),
+	)
+}
+
+func useImportParams() bind.ActionBinder[*ImportParams] {
+	requestBinder := useRequest()
+	return newParams(cli.Pipeline(
+		requestBinder,
+		cli.AddFlags([]*cli.Flag{
+			{
+				Name:     "name",
+				HelpText: "Set the name of the resource",
+			},
+			{
+				Name:     "title",
+				HelpText: "Set the title of the resource",
+			},
+			{
+				Name:     "description",
+				HelpText: "Set the description of the resource",
+			},
+			{
+				Name:       "from",
+				HelpText:   "Import from the given {FORMAT}, which is detected when not specified",
+				Value:      new(string),
+				Completion: cli.ValueCompletion(slices.Sorted(maps.Keys(importFormats))...),
+			},
+			{
+				Name:     "protoset",
+				HelpText: "Record {FILE} as the protoset of services imported from a protoset instead of using server reflection",
+				Value:    new(string),
+			},
+			{
+				Name:     "write",
+				Aliases:  []string{"w"},
+				HelpText: "Write the result into the service configuration in the workspace",
+				Value:    new(bool),
+			},
+		}...),
+	),
+		func(c *cli.Context) (*ImportParams, error) {
+			r, err := requestBinder.Bind(c)
+			return &ImportParams{
+				Request:     r,
+				Name:        c.String("name"),
+				Title:       c.String("title"),
+				Description: c.String("description"),
+				From:        c.String("from"),
+				ProtoSet:    c.String("protoset"),
+				Write:       c.Bool("write"),
+			}, err
+		},
+	)
+}
+
+func importSpec(c *cli.Context, params *ImportParams) error {
+	in, err := io.ReadAll(c.Stdin)
+	if err != nil {
+		return err
+	}
+
+	from := params.From
+	if from == "" {
+		from, err = detectImportFormat(in)
+		if err != nil {
+			return err
+		}
+	}
+	format, ok := importFormats[from]
+	if !ok {
+		return fmt.Errorf("unknown import format %q", from)
+	}
+
+	svc, err := format.imp(in, params)
+	if err != nil {
+		return err
+	}
+
+	if params.Write {
+		name, err := contextual.Workspace(c).Import(svc)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Stderr, "imported into %s\n", name)
+		return nil
+	}
+
+	data, err := yaml.Marshal(&config.File{Service: svc})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.Stdout, string(data))
+
+	return nil
+}
+
+func detectImportFormat(data []byte) (string, error) {
+	for _, name := range slices.Sorted(maps.Keys(importFormats)) {
+		if importFormats[name].detect(data) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("cannot detect the format of the input; specify it using --from")
+}
+
+func hasPrefix(prefix string) func([]byte) bool {
+	return func(data []byte) bool {
+		return bytes.HasPrefix(bytes.TrimSpace(data), []byte(prefix))
+	}
+}
+
+func isOpenAPI(data []byte) bool {
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+	}
+	return yaml.Unmarshal(data, &doc) == nil && doc.OpenAPI != ""
+}
+
+func importFetch(in []byte, params *ImportParams) (*config.Service, error) {
+	call, err := model.ParseJSFetchCall(string(in))
+	if err != nil {
+		return nil, err
+	}
+	if call.Options.Method == "" {
+		call.Options.Method = "GET"
+	}
+
+	return importEndpoint(params, call.ToEndpoint(), call.URL, call.Server())
+}
+
+func importCurl(in []byte, params *ImportParams) (*config.Service, error) {
+	cmd, err := model.ParseCurlCommand(string(in))
+	if err != nil {
+		return nil, err
+	}
+
+	return importEndpoint(params, cmd.ToEndpoint(), cmd.URL, cmd.Server())
+}
+
+// importEndpoint creates the service which contains the endpoint at the
+// resource named by the params. The URL of the request provides the server,
+// the URI of the resource, and the query of the endpoint.
+func importEndpoint(params *ImportParams, endpoint *model.Endpoint, rawURL string, server *model.Server) (*config.Service, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Name = params.Name
+	endpoint.Description = params.Description
+	endpoint.Title = params.Title
+	if params.Method != "" {
+		endpoint.Method = params.Method
+	}
+	if len(u.Query()) > 0 {
+		endpoint.Query = u.Query()
+	}
+
+	ss := *params.Spec
+	var servers []*model.Server
+	if server == nil && params.Server != "" {
+		server = &model.Server{}
+	}
+	if server != nil {
+		server.Name = cmp.Or(params.Server, "default")
+		servers = append(servers, server)
+	}
+
+	mo := &model.Model{
+		Services: []*model.Service{
+			{
+				Name:     ss[0],
+				Resource: &model.Resource{},
+				Servers:  servers,
+			},
+		},
+	}
+
+	current := mo.Services[0].Resource
+	for _, s := range ss[1:] {
+		newChild := &model.Resource{
+			Name: s,
+		}
+		current.Resources = append(current.Resources, newChild)
+		current = newChild
+	}
+	if p := strings.TrimPrefix(u.EscapedPath(), "/"); p != "" {
+		current.URITemplate, err = uritemplates.Parse(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	current.Endpoints = append(current.Endpoints, endpoint)
+
+	return &model.ToConfig(mo).Services[0], nil
+}
+
+// importGRPC imports the services from a protoset or, when the input is the
+// address of a server, using server reflection
+func importGRPC(in []byte, params *ImportParams) (*config.Service, error) {
+	name := (*params.Spec)[0]
+	if grpcclient.IsProtoSet(in) {
+		if params.ProtoSet == "" {
+			log.Warn("warning: server reflection is required by the service because --protoset was not specified")
+		}
+		return grpcclient.ImportProtoSet(in, name, params.ProtoSet)
+	}
+
+	u, plaintext, err := parseGRPCAddress(string(in))
+	if err != nil {
+		return nil, err
+	}
+
+	client := grpcclient.New(
+		grpcclient.WithAddr(u.Host),
+		grpcclient.WithPlaintext(plaintext),
+		grpcclient.WithTLSConfig(&tls.Config{}),
+	)
+	svc, err := client.ImportConfig(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	svc.Client.GRPC.Plaintext = plaintext
+	svc.Servers = []config.Server{
+		{
+			Name:    cmp.Or(params.Server, "default"),
+			BaseURL: u.String(),
+		},
+	}
+	return svc, nil
+}
+
+// parseGRPCAddress parses the address of a gRPC server. As with other gRPC
+// tools, the grpc scheme indicates plaintext whereas the grpcs scheme indicates
+// TLS, which is also used when the scheme is omitted.
+func parseGRPCAddress(s string) (*url.URL, bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, false, fmt.Errorf("input must be a protoset or the address of a gRPC server")
+	}
+	if !strings.Contains(s, "://") {
+		s = "grpcs://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, false, err
+	}
+	switch u.Scheme {
+	case "grpc":
+		return u, true, nil
+	case "grpcs":
+		return u, false, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported scheme %q for gRPC server address", u.Scheme)
+	}
+}
+
+func importOpenAPI(in []byte, params *ImportParams) (*config.Service, error) {
+	doc, err := openapi.Parse(in)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.ToConfig(doc, (*params.Spec)[0]), nil
+}
+
+func importPostman(in []byte, params *ImportParams) (*config.Service, error) {
+	c, err := collection.ParsePostman(in)
+	if err != nil {
+		return nil, err
+	}
+	return c.ToConfig((*params.Spec)[0]), nil
+}
+
+func importPostmanEnvironment(in []byte, params *ImportParams) (*config.Service, error) {
+	env, err := collection.ParsePostmanEnvironment(in)
+	if err != nil {
+		return nil, err
+	}
+	return env.ToConfig((*params.Spec)[0]), nil
+}
+
+func importInsomnia(in []byte, params *ImportParams) (*config.Service, error) {
+	e, err := collection.ParseInsomnia(in)
+	if err != nil {
+		return nil, err
+	}
+	return e.ToConfig((*params.Spec)[0]), nil
+}
+
+func importHAR(in []byte, params *ImportParams) (*config.Service, error) {
+	h, err := collection.ParseHAR(in)
+	if err != nil {
+		return nil, err
+	}
+	return h.ToConfig((*params.Spec)[0]), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Import", func() {
+
+	DescribeTable("detects the format", func(data string, expected string) {
+		format, err := phttpclient.DetectImportFormat(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(format).To(Equal(expected))
+	},
+		Entry("curl", "  curl https://example.com", "curl"),
+		Entry("fetch", `fetch("https://example.com", {})`, "fetch"),
+		Entry("OpenAPI YAML", "openapi: 3.1.0\ninfo: {title: a}", "openapi"),
+		Entry("OpenAPI JSON", `{"openapi": "3.0.0"}`, "openapi"),
+		Entry("Postman", `{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"}}`, "postman"),
+		Entry("Postman environment", `{"name": "a", "_postman_variable_scope": "environment"}`, "postman-environment"),
+		Entry("HAR", `{"log": {"version": "1.2", "entries": []}}`, "har"),
+		Entry("Insomnia", `{"_type": "export", "__export_format": 4}`, "insomnia"),
+	)
+
+	It("returns an error when the format can't be detected", func() {
+		_, err := phttpclient.DetectImportFormat("GET https://example.com")
+		Expect(err).To(MatchError(ContainSubstring("specify it using --from")))
+	})
+
+	It("imports the URL of a curl command", func() {
+		svc, err := phttpclient.ImportAs("curl",
+			`curl -X POST 'https://api.example.com/v1/users?verbose=1' -H 'Content-Type: application/json' -d '{"name":"a"}'`,
+			"example", "users",
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc).To(Equal(&config.Service{
+			Name: "example",
+			Servers: []config.Server{
+				{Name: "default", BaseURL: "https://api.example.com"},
+			},
+			Resources: []config.Resource{
+				{
+					Name: "users",
+					URI:  "v1/users",
+					Post: &config.Endpoint{
+						Headers: config.Header{"Content-Type": {"application/json"}},
+						Query:   config.Header{"verbose": {"1"}},
+						Body:    map[string]any{"name": "a"},
+					},
+				},
+			},
+		}))
+	})
+
+	DescribeTable("parses gRPC server address", func(address string, expected string, plaintext bool) {
+		u, p, err := phttpclient.ParseGRPCAddress(address)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u).To(Equal(expected))
+		Expect(p).To(Equal(plaintext))
+	},
+		Entry("grpc scheme", "grpc://localhost:50051", "grpc://localhost:50051", true),
+		Entry("grpcs scheme", "grpcs://api.example.com:443", "grpcs://api.example.com:443", false),
+		Entry("no scheme", " api.example.com:443\n", "grpcs://api.example.com:443", false),
+	)
+
+	It("returns an error when gRPC input is missing", func() {
+		_, err := phttpclient.ImportAs("grpc", "", "example")
+		Expect(err).To(MatchError("input must be a protoset or the address of a gRPC server"))
+	})
+})
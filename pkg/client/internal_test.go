@@ -4,9 +4,16 @@
 package client // intentional
 
 import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
 	"net/url"
+	"time"
 
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
 	"github.com/Carbonfrost/joe-cli-http/uritemplates"
+	"github.com/Carbonfrost/pastiche/pkg/config"
 	"github.com/Carbonfrost/pastiche/pkg/model"
 	"github.com/Carbonfrost/pastiche/pkg/model/modelfakes"
 )
@@ -19,7 +26,7 @@ func NewLocation(
 	req *model.Request,
 	u *url.URL) *pasticheLocation {
 
-	loc, _ := newLocation(nil, nil, &modelfakes.FakeResolvedResource{
+	loc, _ := newLocation(context.Background(), nil, nil, &modelfakes.FakeResolvedResource{
 		ResourceStub: func() *model.Resource {
 			return resource
 		},
@@ -35,11 +42,104 @@ func NewLocation(
 		EvalRequestStub: func(u *url.URL, m map[string]any) (*model.Request, error) {
 			return req, nil
 		},
-	})
+	}, nil)
 	return loc
 }
 
 func NewLocationVars(vars uritemplates.Vars, r model.ResolvedResource) *pasticheLocation {
-	loc, _ := newLocation(nil, vars, r)
+	loc, _ := newLocation(context.Background(), nil, vars, r, nil)
 	return loc
 }
+
+func SetStep(r httpclient.LocationResolver, f *model.Flow, s *model.Step) {
+	r.(*serviceResolver).setStep(f, s)
+}
+
+func Capture(r httpclient.LocationResolver, headers map[string][]string, body string, captures ...*model.Capture) error {
+	h := &history{
+		Response: historyResponse{
+			Headers: headers,
+			Body:    &historyResponseBody{bytes.NewBufferString(body)},
+		},
+	}
+	return capture(context.Background(), r.(*serviceResolver), h, captures)
+}
+
+func VerifyExpectation(e *model.Expectation, statusCode int, headers map[string][]string, body string, latency time.Duration) error {
+	h := &history{
+		Response: historyResponse{
+			StatusCode: statusCode,
+			Headers:    headers,
+			Body:       &historyResponseBody{bytes.NewBufferString(body)},
+		},
+	}
+	return verifyExpectation(context.Background(), e, h, latency)
+}
+
+func Expectations(c *Client) []*model.Expectation {
+	return c.expectations
+}
+
+func NewMetadataFilterDownloader(f Filter, d httpclient.Downloader, statusCode int) httpclient.Downloader {
+	return NewFilterDownloader(f, d, func(context.Context, *httpclient.Response) (*history, io.Writer) {
+		return &history{
+			Response: historyResponse{
+				StatusCode: statusCode,
+				Body:       &historyResponseBody{new(bytes.Buffer)},
+			},
+		}, nil
+	})
+}
+
+func SelectVarSets(r httpclient.LocationResolver, names ...string) {
+	r.(*serviceResolver).varSets = func(context.Context) []string {
+		return names
+	}
+}
+
+func ResolveSpec(r httpclient.LocationResolver, spec ...string) (*url.URL, error) {
+	loc, err := r.(*serviceResolver).resolveSpec(context.Background(), model.ServiceSpec(spec))
+	if err != nil {
+		return nil, err
+	}
+	return loc.u, nil
+}
+
+func ExtractToken(extract model.OutputFilter, contentType string, body string) (string, error) {
+	return extractToken(context.Background(), extract, []byte(body), contentType)
+}
+
+func SignAWSSigV4(r *http.Request, a *model.AWSSigV4Auth, now time.Time) error {
+	return signAWSSigV4(r, a, now)
+}
+
+func NewDigestTransport(base http.RoundTripper) http.RoundTripper {
+	return &digestTransport{Base: base}
+}
+
+func DigestAuthorization(challenge string, a *model.DigestAuth, method, uri, body, cnonce string) (string, error) {
+	c, err := parseDigestChallenge([]string{challenge})
+	if err != nil {
+		return "", err
+	}
+	return digestAuthorization(c, a, method, uri, []byte(body), cnonce)
+}
+
+func DetectImportFormat(data string) (string, error) {
+	return detectImportFormat([]byte(data))
+}
+
+func ImportAs(format, data string, spec ...string) (*config.Service, error) {
+	ss := model.ServiceSpec(spec)
+	return importFormats[format].imp([]byte(data), &ImportParams{
+		Request: &Request{Spec: &ss},
+	})
+}
+
+func ParseGRPCAddress(s string) (string, bool, error) {
+	u, plaintext, err := parseGRPCAddress(s)
+	if err != nil {
+		return "", false, err
+	}
+	return u.String(), plaintext, nil
+}
@@ -30,10 +30,19 @@ type (
 		Request   historyRequest  `json:"request"`
 		Vars      map[string]any  `json:"vars,omitempty"`
 		BaseURL   *string         `json:"baseUrl"`
+		Stream    *historyStream  `json:"stream,omitempty"`
+	}
+
+	// historyStream identifies the message of a server stream and when the
+	// stream started
+	historyStream struct {
+		Index int       `json:"index"`
+		Start time.Time `json:"start"`
 	}
 
 	historyResponse struct {
 		Headers    map[string][]string  `json:"headers,omitempty"`
+		Trailers   map[string][]string  `json:"trailers,omitempty"`
 		Status     string               `json:"status"`
 		StatusCode int                  `json:"statusCode"`
 		Body       *historyResponseBody `json:"body"`
@@ -53,6 +62,7 @@ type historyDownloader struct {
 	httpclient.Downloader
 
 	factory historyGenerator
+	observe func(*history)
 }
 
 type historyWriter struct {
@@ -60,14 +70,16 @@ type historyWriter struct {
 	logDir  string
 	output  io.Closer
 	history *history
+	observe func(*history)
 }
 
 type historyGenerator func(context.Context, *httpclient.Response) (history *history, responseBody io.Writer)
 
-func newHistoryDownloader(d httpclient.Downloader, factory historyGenerator) httpclient.Downloader {
+func newHistoryDownloader(d httpclient.Downloader, factory historyGenerator, observe func(*history)) httpclient.Downloader {
 	return historyDownloader{
 		Downloader: d,
 		factory:    factory,
+		observe:    observe,
 	}
 }
 
@@ -89,10 +101,15 @@ func (h historyDownloader) OpenDownload(ctx context.Context, r *httpclient.Respo
 		Writer:  io.MultiWriter(output, responseBody),
 		output:  c,
 		history: history,
+		observe: h.observe,
 	}, nil
 }
 
 func (w *historyWriter) Close() error {
+	if w.observe != nil {
+		w.observe(w.history)
+	}
+
 	fileName := filepath.Join(w.logDir, fmt.Sprintf("requests.%s.json", time.Now().Format("2006-01-02")))
 
 	// TODO Improve handling of errors
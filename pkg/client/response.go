@@ -56,9 +56,10 @@ func (*jsonResponse) Document() (any, error) {
 
 func (j *jsonResponse) Data() (any, error) {
 	var data any
-	err := json.Unmarshal(j.data, &data)
-	if err != nil {
-		return nil, err
+	if len(j.data) > 0 {
+		if err := json.Unmarshal(j.data, &data); err != nil {
+			return nil, err
+		}
 	}
 
 	// If the history log was present, then wrap it in a metadata response
@@ -10,12 +10,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/Carbonfrost/pastiche/pkg/credential"
+	"github.com/Carbonfrost/pastiche/pkg/grpcclient"
 	"github.com/Carbonfrost/pastiche/pkg/model"
 )
 
@@ -42,6 +46,16 @@ type serviceResolver struct {
 	vars   map[string]any
 	base   *url.URL
 	config func(context.Context) *model.Model
+
+	// varSets provides the selections of var sets to apply
+	varSets func(context.Context) []string
+
+	// flow and step are set while a step of a flow is being run
+	flow *model.Flow
+	step *model.Step
+
+	// web is set while a gRPC-Web or Connect call is being made
+	web *grpcclient.WebCodec
 }
 
 type pasticheLocation struct {
@@ -99,22 +113,34 @@ func (s *serviceResolver) SetBaseURL(base *url.URL) error {
 }
 
 func (s *serviceResolver) Resolve(c context.Context) ([]httpclient.Location, error) {
-	spec := *s.root(c)
+	if s.step == nil {
+		spec := *s.root(c)
 
-	if looksLikeURL(spec[0]) {
-		r := httpclient.NewDefaultLocationResolver()
-		for _, s := range spec {
-			r.Add(s)
+		if looksLikeURL(spec[0]) {
+			r := httpclient.NewDefaultLocationResolver()
+			for _, s := range spec {
+				r.Add(s)
+			}
+			return r.Resolve(c)
 		}
-		return r.Resolve(c)
 	}
 
-	merged, err := s.config(c).Resolve(spec, s.server(c), s.method(c))
+	merged, err := s.resolveResource(c)
 	if err != nil {
 		return nil, err
 	}
 
-	location, err := newLocation(s.base, s.vars, merged)
+	vars, err := s.requestVars(c, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	var web httpclient.Middleware
+	if s.web != nil {
+		web = s.web.Middleware()
+	}
+
+	location, err := newLocation(c, s.base, vars, merged, web)
 	if err != nil {
 		return nil, err
 	}
@@ -124,22 +150,94 @@ func (s *serviceResolver) Resolve(c context.Context) ([]httpclient.Location, err
 	}, nil
 }
 
+// resolveSpec resolves another spec using the server and vars that were
+// selected, such as the spec which provides a token for auth
+func (s *serviceResolver) resolveSpec(c context.Context, spec model.ServiceSpec) (*pasticheLocation, error) {
+	merged, err := s.config(c).Resolve(spec, s.server(c), "")
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := s.requestVars(c, merged)
+	if err != nil {
+		return nil, err
+	}
+	return newLocation(c, s.base, vars, merged, nil)
+}
+
 func (s *serviceResolver) resolveRequest(c context.Context) (*model.Request, error) {
-	spec := *s.root(c)
-	merged, err := s.config(c).Resolve(spec, s.server(c), s.method(c))
+	merged, err := s.resolveResource(c)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := s.requestVars(c, merged)
 	if err != nil {
 		return nil, err
 	}
 
-	return merged.EvalRequest(s.base, s.vars)
+	return merged.EvalRequest(s.base, vars)
+}
+
+// requestVars gets the vars from the selected var sets combined with
+// the vars that were set explicitly, which take precedence
+func (s *serviceResolver) requestVars(c context.Context, r model.ResolvedResource) (map[string]any, error) {
+	if s.varSets == nil {
+		return s.vars, nil
+	}
+	return selectVars(s.config(c), r, s.varSets(c), s.vars)
 }
 
 func (s *serviceResolver) resolveResource(c context.Context) (model.ResolvedResource, error) {
+	if s.step != nil {
+		return s.config(c).ResolveStep(s.flow, s.step, s.server(c))
+	}
 	spec := *s.root(c)
 	return s.config(c).Resolve(spec, s.server(c), s.method(c))
 }
 
-func newLocation(base *url.URL, vars map[string]any, resolved model.ResolvedResource) (*pasticheLocation, error) {
+// spec gets the service spec that is being requested, which could be the
+// one that is addressed by the current step of a flow
+func (s *serviceResolver) spec(c context.Context) model.ServiceSpec {
+	switch t := s.stepType().(type) {
+	case *model.SpecStep:
+		spec, _ := t.ServiceSpec()
+		return spec
+	case *model.URLStep:
+		return model.ServiceSpec{t.URL}
+	}
+	return *s.root(c)
+}
+
+func (s *serviceResolver) setStep(f *model.Flow, step *model.Step) {
+	s.flow = f
+	s.step = step
+}
+
+func (s *serviceResolver) stepType() model.StepType {
+	if s.step == nil {
+		return nil
+	}
+	return s.step.StepType
+}
+
+func selectVars(mo *model.Model, r model.ResolvedResource, varSets []string, vars map[string]any) (map[string]any, error) {
+	if len(varSets) == 0 {
+		return vars, nil
+	}
+
+	res, err := mo.SelectVarSets(r, varSets...)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(res, vars)
+	return res, nil
+}
+
+// newLocation creates the location of the resolved resource. When it is set,
+// the web middleware converts the request after its body has been set and
+// before it is signed by the auth.
+func newLocation(ctx context.Context, base *url.URL, vars map[string]any, resolved model.ResolvedResource, web httpclient.Middleware) (*pasticheLocation, error) {
 	merged, err := resolved.EvalRequest(base, vars)
 	if err != nil {
 		return nil, err
@@ -166,7 +264,8 @@ func newLocation(base *url.URL, vars map[string]any, resolved model.ResolvedReso
 			httpclient.WithHeaders(merged.Headers),
 			endpointMethod,
 			withBody(merged.Body),
-			withAuth(merged.Auth),
+			web,
+			withAuth(ctx, merged.Auth),
 		),
 		resolved: resolved,
 		u:        loc,
@@ -197,15 +296,43 @@ func withBody(body io.ReadCloser) httpclient.MiddlewareFunc {
 	}
 }
 
-func withAuth(a model.Auth) httpclient.MiddlewareFunc {
+// withAuth signs the request using the auth. Credentials which must be
+// acquired are resolved only once the request is sent.
+func withAuth(ctx context.Context, a model.Auth) httpclient.MiddlewareFunc {
 	if a == nil {
 		return nil
 	}
 	return func(r *http.Request) error {
-		switch auth := a.(type) {
+		resolved, err := credential.Resolve(ctx, a)
+		if err != nil {
+			return err
+		}
+
+		switch auth := resolved.(type) {
 		case *model.BasicAuth:
 			encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth.User + ":" + auth.Password))
 			r.Header.Add("authorization", "Basic "+encodedAuth)
+
+		case *model.BearerAuth:
+			r.Header.Add("authorization", "Bearer "+auth.Token)
+
+		case *model.APIKeyAuth:
+			if auth.Query != "" {
+				// Append so that the existing query is left as is
+				param := url.QueryEscape(auth.Query) + "=" + url.QueryEscape(auth.Key)
+				if r.URL.RawQuery != "" {
+					param = "&" + param
+				}
+				r.URL.RawQuery += param
+			} else {
+				r.Header.Set(auth.HeaderName(), auth.Key)
+			}
+
+		case *model.DigestAuth:
+			return prepareDigest(r, auth)
+
+		case *model.AWSSigV4Auth:
+			return signAWSSigV4(r, auth, time.Now())
 		}
 		return nil
 	}
@@ -7,6 +7,7 @@ package client_test
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
@@ -112,6 +113,123 @@ var _ = Describe("ServiceResolver", func() {
 		)
 	})
 
+	Describe("Resolve with var sets", func() {
+
+		var (
+			varSetModel = &model.Model{
+				VarSets: []*model.VarSet{
+					{
+						Name: "tenants",
+						Vars: map[string]map[string]any{
+							"acme":   {"tenant": "acme", "id": "1"},
+							"globex": {"tenant": "globex"},
+						},
+					},
+				},
+				Services: []*model.Service{
+					{
+						Name: "tenant",
+						Resource: &model.Resource{
+							URITemplate: mustParseURITemplate("https://example.com/{tenant}/{id}"),
+							Endpoints: []*model.Endpoint{
+								{Vars: map[string]any{"id": "0"}},
+							},
+						},
+					},
+				},
+			}
+			contextOfVarSetModel = func(context.Context) *model.Model {
+				return varSetModel
+			}
+		)
+
+		DescribeTable("examples", func(varSets []string, vars map[string]any, expected string) {
+			r := phttpclient.NewServiceResolver(contextOfVarSetModel, specTo("tenant"), stringTo(""), stringTo(""))
+			phttpclient.SelectVarSets(r, varSets...)
+			for k, v := range vars {
+				_ = r.AddVar(k, v)
+			}
+
+			loc, err := r.Resolve(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, u, _ := loc[0].URL(context.Background())
+			Expect(u.String()).To(Equal(expected))
+		},
+			Entry("applies var set", []string{"tenants.acme"}, nil, "https://example.com/acme/1"),
+			Entry("endpoint vars are defaults", []string{"globex"}, nil, "https://example.com/globex/0"),
+			Entry("explicit vars win", []string{"acme"}, map[string]any{"id": "2"}, "https://example.com/acme/2"),
+		)
+
+		It("returns an error on unknown var set", func() {
+			r := phttpclient.NewServiceResolver(contextOfVarSetModel, specTo("tenant"), stringTo(""), stringTo(""))
+			phttpclient.SelectVarSets(r, "initech")
+
+			_, err := r.Resolve(context.Background())
+			Expect(err).To(MatchError(`var set not found: "initech"`))
+		})
+	})
+
+	Describe("ResolveSpec", func() {
+
+		var (
+			loginModel = &model.Model{
+				Services: []*model.Service{
+					{
+						Name: "login",
+						Servers: []*model.Server{
+							{Name: "default", BaseURL: "https://login.example/"},
+							{Name: "staging", BaseURL: "https://login.staging.example/"},
+						},
+						Resource: &model.Resource{
+							URITemplate: mustParseURITemplate("{tenant}"),
+							Endpoints: []*model.Endpoint{
+								{},
+							},
+						},
+					},
+				},
+			}
+			contextOfLoginModel = func(context.Context) *model.Model {
+				return loginModel
+			}
+		)
+
+		It("uses the selected server and vars", func() {
+			r := phttpclient.NewServiceResolver(contextOfLoginModel, specTo("app"), stringTo("staging"), stringTo(""))
+			_ = r.AddVar("tenant", "acme")
+
+			u, err := phttpclient.ResolveSpec(r, "login")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(u.String()).To(Equal("https://login.staging.example/acme"))
+		})
+	})
+
+	Describe("Resolve with step", func() {
+
+		DescribeTable("examples", func(step *model.Step, expected string) {
+			flow := &model.Flow{Steps: []*model.Step{step}}
+			r := phttpclient.NewServiceResolver(contextOfModel, specTo(), stringTo(""), stringTo(""))
+			phttpclient.SetStep(r, flow, step)
+
+			loc, err := r.Resolve(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+
+			_, u, _ := loc[0].URL(context.Background())
+			Expect(u.String()).To(Equal(expected))
+		},
+			Entry("spec step",
+				&model.Step{StepType: &model.SpecStep{Spec: "@example/test"}},
+				"https://foo.example/"),
+			Entry("URL step",
+				&model.Step{
+					StepType: &model.URLStep{URL: "https://bar.example/{id}"},
+					Vars:     map[string]any{"id": "1"},
+				},
+				"https://bar.example/1"),
+		)
+	})
+
 })
 
 var _ = Describe("pasticheLocation", func() {
@@ -162,6 +280,90 @@ var _ = Describe("pasticheLocation", func() {
 	})
 })
 
+var _ = Describe("withAuth", func() {
+
+	DescribeTable("examples", func(auth model.Auth, expectedURL string, expectedHeader http.Header) {
+		ctx := phttpclient.NewLocation(
+			nil,
+			nil,
+			nil,
+			&model.Endpoint{},
+			&model.Request{Auth: auth},
+			nil)
+
+		req, _ := http.NewRequest("GET", "https://example.com?a=1", nil)
+		_ = ctx.Middleware.Handle(req, nil)
+
+		Expect(req.URL.String()).To(Equal(expectedURL))
+		Expect(req.Header).To(Equal(expectedHeader))
+	},
+		Entry("basic",
+			&model.BasicAuth{User: "u", Password: "p"},
+			"https://example.com?a=1",
+			http.Header{"Authorization": {"Basic dTpw"}},
+		),
+		Entry("bearer",
+			&model.BearerAuth{Token: "t"},
+			"https://example.com?a=1",
+			http.Header{"Authorization": {"Bearer t"}},
+		),
+		Entry("API key default header",
+			&model.APIKeyAuth{Key: "k"},
+			"https://example.com?a=1",
+			http.Header{"X-Api-Key": {"k"}},
+		),
+		Entry("API key header",
+			&model.APIKeyAuth{Key: "k", Header: "X-Token"},
+			"https://example.com?a=1",
+			http.Header{"X-Token": {"k"}},
+		),
+		Entry("API key query",
+			&model.APIKeyAuth{Key: "k", Query: "key"},
+			"https://example.com?a=1&key=k",
+			http.Header{},
+		),
+		Entry("API key query escaped",
+			&model.APIKeyAuth{Key: "k&v", Query: "api key"},
+			"https://example.com?a=1&api+key=k%26v",
+			http.Header{},
+		),
+	)
+
+	It("keeps the order and encoding of the existing query", func() {
+		loc := phttpclient.NewLocation(
+			nil,
+			nil,
+			nil,
+			&model.Endpoint{},
+			&model.Request{Auth: &model.APIKeyAuth{Key: "k", Query: "key"}},
+			nil)
+
+		req, _ := http.NewRequest("GET", "https://example.com?b=2&a=%7E", nil)
+		_ = loc.Middleware.Handle(req, nil)
+
+		Expect(req.URL.RawQuery).To(Equal("b=2&a=%7E&key=k"))
+	})
+
+	It("doesn't acquire credentials until the request is sent", func() {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+		}))
+		defer server.Close()
+
+		loc := phttpclient.NewLocation(
+			nil,
+			nil,
+			nil,
+			&model.Endpoint{},
+			&model.Request{Auth: &model.OAuth2Auth{TokenURL: server.URL}},
+			nil)
+
+		Expect(loc).NotTo(BeNil())
+		Expect(requests).To(BeZero())
+	})
+})
+
 func mustParseURITemplate(t string) *uritemplates.URITemplate {
 	u, err := uritemplates.Parse(t)
 	if err != nil {
@@ -0,0 +1,198 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+)
+
+// signAWSSigV4 signs the request using AWS Signature Version 4. The host,
+// content type, and any X-Amz-* headers are signed.
+func signAWSSigV4(r *http.Request, a *model.AWSSigV4Auth, now time.Time) error {
+	var (
+		region       = cmp.Or(a.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+		accessKeyID  = cmp.Or(a.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID"))
+		secretKey    = cmp.Or(a.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY"))
+		sessionToken = cmp.Or(a.SessionToken, os.Getenv("AWS_SESSION_TOKEN"))
+	)
+	switch {
+	case region == "":
+		return errors.New("awsSigV4: region is required")
+	case a.Service == "":
+		return errors.New("awsSigV4: service is required")
+	case accessKeyID == "" || secretKey == "":
+		return errors.New("awsSigV4: access key ID and secret access key are required")
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	date := amzDate[:8]
+	payloadHash := hexSHA256(body)
+
+	r.Header.Set("X-Amz-Date", amzDate)
+	if sessionToken != "" {
+		r.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+	if a.Service == "s3" {
+		r.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := sigV4CanonicalHeaders(r)
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		sigV4CanonicalURI(r.URL, a.Service),
+		sigV4CanonicalQuery(r.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, a.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + secretKey)
+	for _, s := range []string{date, region, a.Service, "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	r.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, accessKeyID, scope, signedHeaders, signature,
+	))
+	return nil
+}
+
+func sigV4CanonicalHeaders(r *http.Request) (string, string) {
+	values := map[string]string{
+		"host": cmp.Or(r.Host, r.URL.Host),
+	}
+	for name, v := range r.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			trimmed := make([]string, len(v))
+			for i := range v {
+				trimmed[i] = strings.Join(strings.Fields(v[i]), " ")
+			}
+			values[name] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + values[name] + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func sigV4CanonicalURI(u *url.URL, service string) string {
+	p := u.Path
+	if p == "" {
+		return "/"
+	}
+
+	// S3 uses the path as is whereas other services normalize it
+	if service != "s3" {
+		clean := path.Clean(p)
+		if strings.HasSuffix(p, "/") && clean != "/" {
+			clean += "/"
+		}
+		p = clean
+	}
+
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		segments[i] = sigV4Escape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sigV4CanonicalQuery(u *url.URL) string {
+	var pairs []string
+	for name, values := range u.Query() {
+		for _, v := range values {
+			pairs = append(pairs, sigV4Escape(name)+"="+sigV4Escape(v))
+		}
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// sigV4Escape encodes everything except the unreserved characters
+func sigV4Escape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// readBody reads the request body and then replaces it so that it can be
+// sent
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
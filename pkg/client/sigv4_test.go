@@ -0,0 +1,84 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SignAWSSigV4", func() {
+
+	// Examples from the AWS Signature Version 4 test suite
+	var (
+		auth = &model.AWSSigV4Auth{
+			Region:          "us-east-1",
+			Service:         "service",
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		}
+		now = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	)
+
+	DescribeTable("examples", func(method, u string, headers http.Header, body string, expected string) {
+		var req *http.Request
+		if body == "" {
+			req, _ = http.NewRequest(method, u, nil)
+		} else {
+			req, _ = http.NewRequest(method, u, strings.NewReader(body))
+		}
+		for k, v := range headers {
+			req.Header[k] = v
+		}
+
+		err := phttpclient.SignAWSSigV4(req, auth, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.Header.Get("X-Amz-Date")).To(Equal("20150830T123600Z"))
+		Expect(req.Header.Get("Authorization")).To(Equal(expected))
+	},
+		Entry("get-vanilla",
+			"GET", "https://example.amazonaws.com/", nil, "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=host;x-amz-date, "+
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		),
+		Entry("get-vanilla-query-order-key-case",
+			"GET", "https://example.amazonaws.com/?Param2=value2&Param1=value1", nil, "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=host;x-amz-date, "+
+				"Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		),
+		Entry("post-vanilla",
+			"POST", "https://example.amazonaws.com/", nil, "",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=host;x-amz-date, "+
+				"Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		),
+		Entry("post-x-www-form-urlencoded",
+			"POST", "https://example.amazonaws.com/",
+			http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+			"Param1=value1",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=content-type;host;x-amz-date, "+
+				"Signature=ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		),
+	)
+
+	It("preserves the body", func() {
+		req, _ := http.NewRequest("POST", "https://example.amazonaws.com/", strings.NewReader("Param1=value1"))
+		_ = phttpclient.SignAWSSigV4(req, auth, now)
+
+		body, _ := io.ReadAll(req.Body)
+		Expect(string(body)).To(Equal("Param1=value1"))
+	})
+})
@@ -0,0 +1,127 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/contextual"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+// fetchSpecToken runs the request for the spec of the auth and applies the
+// extract filter to its response to obtain the token.
+func fetchSpecToken(ctx context.Context, a *model.SpecAuth) (string, error) {
+	spec, err := model.ParseServiceSpec(a.Spec)
+	if err != nil {
+		return "", fmt.Errorf("fromSpec: %w", err)
+	}
+
+	loc, err := specLocation(ctx, spec)
+	if err != nil {
+		return "", fmt.Errorf("fromSpec: %w", err)
+	}
+
+	data, ct, err := doSpecRequest(ctx, loc)
+	if err != nil {
+		return "", fmt.Errorf("fromSpec %q: %w", a.Spec, err)
+	}
+
+	token, err := extractToken(ctx, a.Extract, data, ct)
+	if err != nil {
+		return "", fmt.Errorf("fromSpec %q: %w", a.Spec, err)
+	}
+	if token == "" {
+		return "", fmt.Errorf("fromSpec %q: no token in response", a.Spec)
+	}
+	return token, nil
+}
+
+// specScope gets the server selected for the command, which scopes the
+// tokens that are cached
+func specScope(ctx context.Context) string {
+	if sr, ok := activeServiceResolver(ctx); ok {
+		return sr.server(ctx)
+	}
+	return ""
+}
+
+// specLocation resolves the spec using the server and vars selected for the
+// command so that the request for the token is made to the same server as
+// the request which requires it
+func specLocation(ctx context.Context, spec model.ServiceSpec) (*pasticheLocation, error) {
+	if sr, ok := activeServiceResolver(ctx); ok {
+		return sr.resolveSpec(ctx, spec)
+	}
+
+	resolved, err := contextual.Workspace(ctx).Model().Resolve(spec, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return newLocation(ctx, nil, nil, resolved, nil)
+}
+
+func activeServiceResolver(ctx context.Context) (*serviceResolver, bool) {
+	c, ok := ctx.Value(servicesKey).(*Client)
+	if !ok {
+		return nil, false
+	}
+	sr, ok := c.locationResolver.(*serviceResolver)
+	return sr, ok
+}
+
+func doSpecRequest(ctx context.Context, loc *pasticheLocation) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", loc.u.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := loc.Handle(req, nil); err != nil {
+		return nil, "", err
+	}
+
+	client := &http.Client{Transport: httpTransport(ctx)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, "", fmt.Errorf("request returned %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// extractToken applies the filter to the response. When the result is a
+// JSON string, its value is used as the token.
+func extractToken(ctx context.Context, extract model.OutputFilter, data []byte, ct string) (string, error) {
+	if extract != nil {
+		f, err := outputFilterToFilter(extract)
+		if err != nil {
+			return "", err
+		}
+
+		data, err = f.Search(ctx, newResponse(data, ct, nil))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var token string
+	if err := json.Unmarshal(data, &token); err == nil {
+		return token, nil
+	}
+	return strings.TrimSpace(string(data)), nil
+}
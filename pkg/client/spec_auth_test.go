@@ -0,0 +1,46 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client_test
+
+import (
+	phttpclient "github.com/Carbonfrost/pastiche/pkg/client"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExtractToken", func() {
+
+	DescribeTable("examples", func(extract model.OutputFilter, ct, body, expected string) {
+		token, err := phttpclient.ExtractToken(extract, ct, body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal(expected))
+	},
+		Entry("JMESPath",
+			&model.JMESPathOutput{Query: "token"},
+			"application/json",
+			`{"token": "t0k3n"}`,
+			"t0k3n",
+		),
+		Entry("dig",
+			&model.DigOutput{Query: "data.session.id"},
+			"application/json",
+			`{"data": {"session": {"id": "s3ss10n"}}}`,
+			"s3ss10n",
+		),
+		Entry("no extract uses body",
+			nil,
+			"text/plain",
+			"t0k3n\n",
+			"t0k3n",
+		),
+	)
+
+	It("returns an error when the response cannot be queried", func() {
+		_, err := phttpclient.ExtractToken(&model.JMESPathOutput{Query: "token"}, "application/xml", "<a/>")
+		Expect(err).To(HaveOccurred())
+	})
+})
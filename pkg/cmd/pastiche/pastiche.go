@@ -55,11 +55,25 @@ func NewApp() *cli.App {
 			{Name: "describe", Uses: client.Describe()},
 			{Name: "serve", Uses: server.Serve()},
 			{Name: "log", Uses: workspace.Log()},
+			{Name: "fmt", Uses: workspace.Fmt()},
 			{Name: "fetch", Uses: client.Do(),
 				Flags: []*cli.Flag{
 					{Uses: client.SetVarFromEnvVar()},
 				}},
+			{Name: "run", Uses: client.Run(),
+				Flags: []*cli.Flag{
+					{Uses: client.SetVarFromEnvVar()},
+				}},
 			{Name: "import", Uses: client.Import()},
+			{Name: "export", Uses: client.Export()},
+			{
+				Name:     "grpc",
+				HelpText: "Inspect the gRPC services provided by the server of a service",
+				Subcommands: []*cli.Command{
+					{Name: "list", Uses: client.GRPCList()},
+					{Name: "describe", Uses: client.GRPCDescribe()},
+				},
+			},
 			{
 				Name: "open",
 				Uses: client.Open(),
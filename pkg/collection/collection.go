@@ -0,0 +1,224 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package collection converts between Pastiche service configuration and
+// the request collections of other API clients such as Postman and
+// Insomnia, as well as HTTP archives saved from browsers.
+package collection
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/internal/log"
+)
+
+var (
+	originPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*://[^/?#]*|\{[^}/]+\}[^/?#]*)`)
+)
+
+// folder is the format-independent representation of a folder of
+// requests, which becomes a resource
+type folder struct {
+	name        string
+	description string
+	vars        map[string]any
+	auth        *config.Auth
+	folders     []*folder
+	requests    []*request
+}
+
+// request is the format-independent representation of a request, which
+// becomes an endpoint. The URL uses URI template syntax and other values
+// use the syntax of Pastiche expressions.
+type request struct {
+	name        string
+	description string
+	method      string
+	url         string
+	headers     config.Header
+	query       config.Header
+	form        config.Form
+	body        any
+	rawBody     any
+	auth        *config.Auth
+}
+
+// toConfig converts the folder to the service with the given name. Each
+// distinct origin of the request URLs becomes a server.
+func (f *folder) toConfig(name string) *config.Service {
+	b := &builder{}
+	svc := &config.Service{
+		Name: name,
+		Metadata: config.Metadata{
+			Title:       f.name,
+			Description: f.description,
+		},
+		Vars: f.vars,
+		Auth: f.auth,
+	}
+	svc.Resources = b.resources(f)
+
+	for i, origin := range b.origins {
+		svc.Servers = append(svc.Servers, config.Server{
+			Name:    serverName(i),
+			BaseURL: origin,
+		})
+	}
+	return svc
+}
+
+type builder struct {
+	origins []string
+}
+
+func (b *builder) resources(f *folder) []config.Resource {
+	var res []config.Resource
+	for _, child := range f.folders {
+		res = append(res, config.Resource{
+			Name: config.Identifier(child.name),
+			Metadata: config.Metadata{
+				Title:       child.name,
+				Description: child.description,
+			},
+			Vars:      child.vars,
+			Auth:      child.auth,
+			Resources: b.resources(child),
+		})
+	}
+
+	// Requests which have the same URI become endpoints of the same resource
+	// unless they have the same method
+	for _, r := range f.requests {
+		uri, query := b.splitURL(r.url)
+		ep := r.endpoint(query)
+
+		i := slices.IndexFunc(res, func(t config.Resource) bool {
+			_, exists := t.Endpoint(r.method)
+			return t.URI == uri && len(t.Resources) == 0 && !exists
+		})
+		if i < 0 {
+			res = append(res, config.Resource{
+				Name: config.Identifier(r.name),
+				URI:  uri,
+			})
+			i = len(res) - 1
+		}
+		res[i].SetEndpoint(r.method, ep)
+	}
+	return res
+}
+
+func (r *request) endpoint(query config.Header) *config.Endpoint {
+	ep := &config.Endpoint{
+		Name: config.Identifier(r.name),
+		Metadata: config.Metadata{
+			Title:       r.name,
+			Description: r.description,
+		},
+		Headers: r.headers,
+		Query:   r.query,
+		Form:    r.form,
+		Body:    r.body,
+		RawBody: r.rawBody,
+		Auth:    r.auth,
+	}
+	for k, v := range query {
+		if ep.Query == nil {
+			ep.Query = config.Header{}
+		}
+		ep.Query[k] = append(ep.Query[k], v...)
+	}
+	return ep
+}
+
+// splitURL records the origin of the URL as a server and returns the
+// URI relative to it and the query
+func (b *builder) splitURL(u string) (string, config.Header) {
+	var query config.Header
+	if path, rawQuery, ok := strings.Cut(u, "?"); ok {
+		u = path
+		if values, err := url.ParseQuery(rawQuery); err == nil && len(values) > 0 {
+			query = config.Header(values)
+		}
+	}
+
+	if m := originPattern.FindString(u); m != "" {
+		origin := m
+		// A var which provides the entire origin must be expanded without
+		// escaping its reserved characters
+		if strings.HasPrefix(origin, "{") && !strings.HasPrefix(origin, "{+") {
+			origin = "{+" + origin[1:]
+		}
+		if !slices.Contains(b.origins, origin) {
+			b.origins = append(b.origins, origin)
+		}
+		u = u[len(m):]
+	}
+	return strings.TrimPrefix(u, "/"), query
+}
+
+func serverName(i int) string {
+	if i == 0 {
+		return "default"
+	}
+	return fmt.Sprintf("server%d", i+1)
+}
+
+// envName gets the name of the var set entry for an environment
+func envName(s string) string {
+	if name := strings.ToLower(config.Identifier(s)); name != "" {
+		return name
+	}
+	return "default"
+}
+
+// decodeBody decodes the text of a body when it is JSON; otherwise, it is
+// used as the raw body
+func decodeBody(text string) (body, rawBody any) {
+	if text == "" {
+		return nil, nil
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+		return decoded, nil
+	}
+	return nil, text
+}
+
+// isMultipart determines whether the content type is multipart form data,
+// which cannot be imported because files and parts can't be represented
+func isMultipart(contentType string) bool {
+	return strings.HasPrefix(contentType, "multipart/form-data")
+}
+
+func warnMultipart(method, url string) {
+	log.Warnf("warning: skipped the body of %s %s because multipart form data cannot be imported", method, url)
+}
+
+// keyValue is a name and value which is disabled in some formats
+type keyValue struct {
+	key      string
+	value    string
+	disabled bool
+}
+
+func toHeader(values []keyValue, convert func(string) string) config.Header {
+	var res config.Header
+	for _, kv := range values {
+		if kv.disabled || kv.key == "" {
+			continue
+		}
+		if res == nil {
+			res = config.Header{}
+		}
+		res[kv.key] = append(res[kv.key], convert(kv.value))
+	}
+	return res
+}
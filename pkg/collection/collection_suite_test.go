@@ -0,0 +1,17 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCollection(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Collection Suite")
+}
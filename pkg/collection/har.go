@@ -0,0 +1,364 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+)
+
+var (
+	idPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^[0-9]+$`),
+		regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		regexp.MustCompile(`^[0-9a-fA-F]*[0-9][0-9a-fA-F]*$`),
+		regexp.MustCompile(`^[A-Za-z0-9_-]*[0-9][A-Za-z0-9_-]*$`),
+	}
+
+	// minIDLength is the minimum length of a segment matched by the
+	// corresponding pattern in idPatterns
+	minIDLength = []int{1, 36, 12, 20}
+)
+
+// HAR is an HTTP archive, as saved from the developer tools of browsers
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string      `json:"version"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HAREntry struct {
+	Request  HARRequest  `json:"request"`
+	Response HARResponse `json:"response"`
+}
+
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+}
+
+type HARResponse struct {
+	Status int `json:"status"`
+}
+
+type HARPostData struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text,omitempty"`
+	Params   []HARNameValue `json:"params,omitempty"`
+}
+
+type HARNameValue struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	FileName string `json:"fileName,omitempty"`
+}
+
+// ParseHAR parses an HTTP archive
+func ParseHAR(data []byte) (*HAR, error) {
+	var h HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, err
+	}
+	if !IsHAR(data) {
+		return nil, errors.New("not an HTTP archive")
+	}
+	return &h, nil
+}
+
+// IsHAR tests whether the data is an HTTP archive
+func IsHAR(data []byte) bool {
+	var h struct {
+		Log *struct {
+			Entries []json.RawMessage `json:"entries"`
+		} `json:"log"`
+	}
+	return json.Unmarshal(data, &h) == nil && h.Log != nil && h.Log.Entries != nil
+}
+
+// ToConfig converts the archive to the service with the given name. Each
+// distinct origin becomes a server. Paths become nested resources with one
+// resource for each segment, where segments which look like IDs become
+// template expressions, and requests become endpoints. When a request is
+// repeated, only the first one is used. Headers which are repeated are
+// moved to the resource which contains them.
+func (h *HAR) ToConfig(name string) *config.Service {
+	b := &builder{}
+	svc := &config.Service{
+		Name: name,
+	}
+	for _, e := range h.Log.Entries {
+		r := e.Request
+		uri, _ := b.splitURL(r.URL)
+		segments := templateSegments(uri)
+
+		method := strings.ToUpper(cmp.Or(r.Method, "GET"))
+		svc.Resources = addHARPath(svc.Resources, segments, method, r.endpoint)
+	}
+	for i := range svc.Resources {
+		hoistHeaders(&svc.Resources[i])
+	}
+
+	for i, origin := range b.origins {
+		svc.Servers = append(svc.Servers, config.Server{
+			Name:    serverName(i),
+			BaseURL: origin,
+		})
+	}
+	return svc
+}
+
+func addHARPath(resources []config.Resource, segments []string, method string, endpoint func() *config.Endpoint) []config.Resource {
+	seg := segments[0]
+	i := slices.IndexFunc(resources, func(r config.Resource) bool { return r.URI == seg })
+	if i < 0 {
+		resources = append(resources, config.Resource{
+			Name: cmp.Or(config.Identifier(seg), "root"),
+			URI:  seg,
+		})
+		i = len(resources) - 1
+	}
+
+	r := &resources[i]
+	if len(segments) > 1 {
+		r.Resources = addHARPath(r.Resources, segments[1:], method, endpoint)
+		return resources
+	}
+	if _, exists := r.Endpoint(method); !exists {
+		r.SetEndpoint(method, endpoint())
+	}
+	return resources
+}
+
+func (r *HARRequest) endpoint() *config.Endpoint {
+	ep := &config.Endpoint{
+		Query: toHeader(harKeyValues(r.QueryString), identity),
+	}
+
+	var headers []keyValue
+	for _, h := range r.Headers {
+		name := http.CanonicalHeaderKey(h.Name)
+		if managedHeader(name) {
+			continue
+		}
+		headers = append(headers, keyValue{name, h.Value, false})
+	}
+	ep.Headers = toHeader(headers, identity)
+
+	if d := r.PostData; d != nil {
+		switch {
+		case isMultipart(d.MimeType):
+			warnMultipart(r.Method, r.URL)
+		case len(d.Params) > 0:
+			ep.Form = config.Form(toHeader(harKeyValues(d.Params), identity))
+		default:
+			ep.Body, ep.RawBody = decodeBody(d.Text)
+		}
+	}
+	return ep
+}
+
+// templateSegments splits the path into segments, replacing the segments
+// which look like IDs with template expressions. The name of the var is
+// derived from the preceding segment, so users/123 becomes users/{userId}.
+func templateSegments(uri string) []string {
+	var res []string
+	seen := map[string]bool{}
+	for s := range strings.SplitSeq(uri, "/") {
+		if s == "" {
+			continue
+		}
+		if isID(s) {
+			name := "id"
+			if len(res) > 0 && !strings.HasPrefix(res[len(res)-1], "{") {
+				name = singular(config.Identifier(res[len(res)-1])) + "Id"
+			}
+			base := name
+			for i := 2; seen[name]; i++ {
+				name = fmt.Sprintf("%s%d", base, i)
+			}
+			seen[name] = true
+			s = "{" + name + "}"
+		}
+		res = append(res, s)
+	}
+	if len(res) == 0 {
+		return []string{"/"}
+	}
+	return res
+}
+
+func isID(s string) bool {
+	for i, p := range idPatterns {
+		if len(s) >= minIDLength[i] && p.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "ss"):
+		return s
+	default:
+		return strings.TrimSuffix(s, "s")
+	}
+}
+
+// managedHeader tests whether the header is managed by the browser, which
+// includes the forbidden request headers of the Fetch standard and the
+// pseudo-headers of HTTP/2
+func managedHeader(name string) bool {
+	if strings.HasPrefix(name, ":") ||
+		strings.HasPrefix(name, "Sec-") ||
+		strings.HasPrefix(name, "Proxy-") {
+		return true
+	}
+	switch name {
+	case "Accept-Charset", "Accept-Encoding", "Access-Control-Request-Headers",
+		"Access-Control-Request-Method", "Connection", "Content-Length",
+		"Cookie", "Cookie2", "Date", "Dnt", "Expect", "Host", "Keep-Alive",
+		"Origin", "Referer", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+		"Via":
+		return true
+	}
+	return false
+}
+
+// member is an endpoint or child resource which has headers. The resource
+// is nil for endpoints.
+type member struct {
+	headers  *config.Header
+	resource *config.Resource
+}
+
+func members(r *config.Resource) []member {
+	var res []member
+	for i := range r.Resources {
+		res = append(res, member{&r.Resources[i].Headers, &r.Resources[i]})
+	}
+	for _, ep := range r.Endpoints() {
+		res = append(res, member{&ep.Headers, nil})
+	}
+	return res
+}
+
+// hoistHeaders moves headers which are shared by most of the endpoints and
+// child resources of the resource into the resource itself, starting from
+// the deepest resources
+func hoistHeaders(r *config.Resource) {
+	for i := range r.Resources {
+		hoistHeaders(&r.Resources[i])
+	}
+	all := members(r)
+
+	names := map[string]bool{}
+	for _, m := range all {
+		for k := range *m.headers {
+			names[k] = true
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		if strings.HasPrefix(name, "+") || strings.HasPrefix(name, "-") ||
+			names["+"+name] || names["-"+name] {
+			continue
+		}
+
+		common, count := mostCommonValues(all, name)
+		if count*2 <= len(all) {
+			continue
+		}
+
+		if r.Headers == nil {
+			r.Headers = config.Header{}
+		}
+		r.Headers[name] = common
+		for _, m := range all {
+			m.inherit(name, common)
+		}
+	}
+}
+
+// inherit updates the member so that it has the same headers once it
+// inherits the values of the header. When the member doesn't have the
+// header, its endpoints remove it using -; when the member has additional
+// values, it adds them using +.
+func (m member) inherit(name string, common []string) {
+	h := m.headers
+	values, ok := (*h)[name]
+	switch {
+	case ok && slices.Equal(values, common):
+		delete(*h, name)
+	case ok && len(values) > len(common) && slices.Equal(values[:len(common)], common):
+		(*h)["+"+name] = values[len(common):]
+		delete(*h, name)
+	case ok:
+	case m.resource != nil:
+		for _, child := range members(m.resource) {
+			child.inherit(name, common)
+		}
+	default:
+		if *h == nil {
+			*h = config.Header{}
+		}
+		(*h)["-"+name] = common
+	}
+	if len(*h) == 0 {
+		*h = nil
+	}
+}
+
+func mostCommonValues(members []member, name string) ([]string, int) {
+	var (
+		common []string
+		count  int
+	)
+	counts := map[string]int{}
+	for _, m := range members {
+		values, ok := (*m.headers)[name]
+		if !ok {
+			continue
+		}
+		key := strings.Join(values, "\x00")
+		counts[key]++
+		if counts[key] > count {
+			common, count = values, counts[key]
+		}
+	}
+	return common, count
+}
+
+func harKeyValues(values []HARNameValue) []keyValue {
+	res := make([]keyValue, 0, len(values))
+	for _, nv := range values {
+		if nv.FileName != "" {
+			continue
+		}
+		res = append(res, keyValue{nv.Name, nv.Value, false})
+	}
+	return res
+}
+
+func identity(s string) string {
+	return s
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection_test
+
+import (
+	"os"
+
+	"github.com/Carbonfrost/pastiche/pkg/collection"
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("HAR", func() {
+
+	var subject = func() *config.Service {
+		data, err := os.ReadFile("testdata/example.har")
+		Expect(err).NotTo(HaveOccurred())
+
+		h, err := collection.ParseHAR(data)
+		Expect(err).NotTo(HaveOccurred())
+		return h.ToConfig("example")
+	}
+
+	It("groups entries by origin into servers", func() {
+		Expect(subject().Servers).To(Equal([]config.Server{
+			{Name: "default", BaseURL: "https://api.example.com"},
+			{Name: "server2", BaseURL: "https://auth.example.com"},
+		}))
+	})
+
+	It("infers templates for segments which look like IDs", func() {
+		users := subject().Resources[0].Resources[0]
+		Expect(users.Resources).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("userId"),
+			"URI":  Equal("{userId}"),
+			"Resources": ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"URI": Equal("orders"),
+				"Resources": ConsistOf(MatchFields(IgnoreExtras, Fields{
+					"URI": Equal("{orderId}"),
+				})),
+			})),
+		})))
+	})
+
+	It("converts requests to endpoints", func() {
+		users := subject().Resources[0].Resources[0]
+		Expect(users.Get).To(Equal(&config.Endpoint{
+			Query: config.Header{"limit": {"10"}},
+		}))
+		Expect(users.Post).To(Equal(&config.Endpoint{
+			Headers: config.Header{"Content-Type": {"application/json"}},
+			Body:    map[string]any{"name": "a"},
+		}))
+		Expect(subject().Resources[1].Post.Form).To(Equal(config.Form{"user": {"a"}}))
+	})
+
+	It("uses the first of repeated requests", func() {
+		user := subject().Resources[0].Resources[0].Resources[0]
+		Expect(user.Get).To(Equal(&config.Endpoint{}))
+	})
+
+	It("moves repeated headers to resources", func() {
+		svc := subject()
+		Expect(svc.Resources[0].Headers).To(Equal(config.Header{
+			"Accept":        {"application/json"},
+			"Authorization": {"Bearer t"},
+		}))
+		Expect(svc.Resources[0].Resources[0].Headers).To(BeNil())
+	})
+
+	It("removes hoisted headers from endpoints which don't have them", func() {
+		order := subject().Resources[0].Resources[0].Resources[0].Resources[0].Resources[0]
+		Expect(order.Get.Headers).To(Equal(config.Header{
+			"-Accept": {"application/json"},
+		}))
+	})
+
+	It("skips multipart form data", func() {
+		h, err := collection.ParseHAR([]byte(`{"log": {"version": "1.2", "entries": [{
+  "request": {
+    "method": "POST",
+    "url": "https://example.com/upload",
+    "postData": {"mimeType": "multipart/form-data; boundary=b", "params": [{"name": "file", "fileName": "a.txt"}]}
+  }
+}]}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(h.ToConfig("example").Resources[0].Post).To(Equal(&config.Endpoint{}))
+	})
+
+	DescribeTable("detects the format", func(data string, expected bool) {
+		Expect(collection.IsHAR([]byte(data))).To(Equal(expected))
+	},
+		Entry("archive", `{"log": {"version": "1.2", "entries": []}}`, true),
+		Entry("no entries", `{"log": {"version": "1.2"}}`, false),
+		Entry("other JSON", `{"info": {}}`, false),
+	)
+})
@@ -0,0 +1,228 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+)
+
+var (
+	insomniaVar = regexp.MustCompile(`\{\{\s*(?:_\.)?([^{}\s]+)\s*\}\}`)
+)
+
+// Insomnia is an Insomnia v4 export
+type Insomnia struct {
+	Type      string              `json:"_type"`
+	Format    int                 `json:"__export_format"`
+	Source    string              `json:"__export_source,omitempty"`
+	Resources []*InsomniaResource `json:"resources"`
+}
+
+// InsomniaResource is a workspace, request group, request, or environment
+// as indicated by its type
+type InsomniaResource struct {
+	ID          string `json:"_id"`
+	ParentID    string `json:"parentId"`
+	Type        string `json:"_type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	Method         string                  `json:"method,omitempty"`
+	URL            string                  `json:"url,omitempty"`
+	Body           *InsomniaBody           `json:"body,omitempty"`
+	Headers        []InsomniaKeyValue      `json:"headers,omitempty"`
+	Parameters     []InsomniaKeyValue      `json:"parameters,omitempty"`
+	Authentication *InsomniaAuthentication `json:"authentication,omitempty"`
+
+	Data map[string]any `json:"data,omitempty"`
+}
+
+type InsomniaBody struct {
+	MimeType string             `json:"mimeType,omitempty"`
+	Text     string             `json:"text,omitempty"`
+	Params   []InsomniaKeyValue `json:"params,omitempty"`
+}
+
+type InsomniaKeyValue struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type InsomniaAuthentication struct {
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	AddTo    string `json:"addTo,omitempty"`
+}
+
+// ParseInsomnia parses an Insomnia v4 export
+func ParseInsomnia(data []byte) (*Insomnia, error) {
+	var e Insomnia
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if !IsInsomnia(data) {
+		return nil, errors.New("not an Insomnia v4 export")
+	}
+	return &e, nil
+}
+
+// IsInsomnia tests whether the data is an Insomnia v4 export
+func IsInsomnia(data []byte) bool {
+	var e Insomnia
+	return json.Unmarshal(data, &e) == nil && e.Type == "export" && e.Format == 4
+}
+
+// ToConfig converts the export to the service with the given name. The
+// first workspace is converted: request groups become resources, requests
+// become endpoints, the base environment provides vars, and each
+// sub-environment becomes an entry in the var set named env.
+func (e *Insomnia) ToConfig(name string) *config.Service {
+	children := map[string][]*InsomniaResource{}
+	var workspace *InsomniaResource
+	for _, r := range e.Resources {
+		if r.Type == "workspace" && workspace == nil {
+			workspace = r
+		}
+		children[r.ParentID] = append(children[r.ParentID], r)
+	}
+	if workspace == nil {
+		workspace = &InsomniaResource{}
+	}
+
+	root := &folder{
+		name:        workspace.Name,
+		description: workspace.Description,
+	}
+	var envs map[string]map[string]any
+	for _, base := range children[workspace.ID] {
+		if base.Type != "environment" {
+			continue
+		}
+		root.vars = base.Data
+		for _, sub := range children[base.ID] {
+			if sub.Type != "environment" {
+				continue
+			}
+			if envs == nil {
+				envs = map[string]map[string]any{}
+			}
+			envs[envName(sub.Name)] = sub.Data
+		}
+	}
+	insomniaItems(root, children, workspace.ID)
+
+	svc := root.toConfig(name)
+	if envs != nil {
+		svc.VarSets = []config.VarSet{{Name: "env", Vars: envs}}
+	}
+	return svc
+}
+
+func insomniaItems(f *folder, children map[string][]*InsomniaResource, parentID string) {
+	for _, r := range children[parentID] {
+		switch r.Type {
+		case "request_group":
+			child := &folder{
+				name:        r.Name,
+				description: r.Description,
+			}
+			insomniaItems(child, children, r.ID)
+			f.folders = append(f.folders, child)
+
+		case "request":
+			f.requests = append(f.requests, r.toRequest())
+		}
+	}
+}
+
+func (r *InsomniaResource) toRequest() *request {
+	res := &request{
+		name:        r.Name,
+		description: r.Description,
+		method:      strings.ToUpper(r.Method),
+		url:         insomniaVar.ReplaceAllString(r.URL, "{$1}"),
+		headers:     toHeader(insomniaKeyValues(r.Headers), insomniaExpr),
+		query:       toHeader(insomniaKeyValues(r.Parameters), insomniaExpr),
+		auth:        r.Authentication.toConfig(),
+	}
+	if res.method == "" {
+		res.method = "GET"
+	}
+
+	if b := r.Body; b != nil {
+		switch {
+		case isMultipart(b.MimeType):
+			warnMultipart(res.method, res.url)
+		case strings.HasPrefix(b.MimeType, "application/x-www-form-urlencoded"):
+			res.form = config.Form(toHeader(insomniaKeyValues(b.Params), insomniaExpr))
+		default:
+			res.body, res.rawBody = decodeBody(insomniaExpr(b.Text))
+		}
+	}
+	return res
+}
+
+func (a *InsomniaAuthentication) toConfig() *config.Auth {
+	if a == nil || a.Disabled {
+		return nil
+	}
+	switch a.Type {
+	case "basic":
+		return &config.Auth{
+			Basic: &config.BasicAuth{
+				User:     insomniaExpr(a.Username),
+				Password: insomniaExpr(a.Password),
+			},
+		}
+	case "bearer":
+		return &config.Auth{
+			Bearer: &config.BearerAuth{Token: insomniaExpr(a.Token)},
+		}
+	case "digest":
+		return &config.Auth{
+			Digest: &config.DigestAuth{
+				User:     insomniaExpr(a.Username),
+				Password: insomniaExpr(a.Password),
+			},
+		}
+	case "apikey":
+		auth := &config.APIKeyAuth{Key: insomniaExpr(a.Value)}
+		if a.AddTo == "queryParams" {
+			auth.Query = a.Key
+		} else {
+			auth.Header = a.Key
+		}
+		return &config.Auth{APIKey: auth}
+	}
+	return nil
+}
+
+func insomniaKeyValues(values []InsomniaKeyValue) []keyValue {
+	res := make([]keyValue, 0, len(values))
+	for _, kv := range values {
+		if kv.Type == "file" {
+			continue
+		}
+		res = append(res, keyValue{kv.Name, kv.Value, kv.Disabled})
+	}
+	return res
+}
+
+// insomniaExpr converts the Insomnia variables in s to Pastiche expressions
+func insomniaExpr(s string) string {
+	return insomniaVar.ReplaceAllString(s, "$${$1}")
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection_test
+
+import (
+	"os"
+
+	"github.com/Carbonfrost/pastiche/pkg/collection"
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Insomnia", func() {
+
+	var subject = func() *config.Service {
+		data, err := os.ReadFile("testdata/insomnia.json")
+		Expect(err).NotTo(HaveOccurred())
+
+		e, err := collection.ParseInsomnia(data)
+		Expect(err).NotTo(HaveOccurred())
+		return e.ToConfig("example")
+	}
+
+	It("converts the workspace and environments", func() {
+		svc := subject()
+		Expect(svc.Title).To(Equal("Example API"))
+		Expect(svc.Description).To(Equal("An example workspace"))
+		Expect(svc.Vars).To(Equal(map[string]any{"baseUrl": "https://api.example.com"}))
+		Expect(svc.VarSets).To(Equal([]config.VarSet{
+			{
+				Name: "env",
+				Vars: map[string]map[string]any{
+					"production": {"baseUrl": "https://prod.example.com"},
+				},
+			},
+		}))
+		Expect(svc.Servers).To(Equal([]config.Server{
+			{Name: "default", BaseURL: "{+baseUrl}"},
+		}))
+	})
+
+	It("converts request groups and requests", func() {
+		Expect(subject().Resources).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("Users"),
+			"Resources": ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"URI": Equal("users"),
+				"Get": Equal(&config.Endpoint{
+					Name:     "List-users",
+					Metadata: config.Metadata{Title: "List users"},
+					Headers:  config.Header{"Accept": {"application/json"}},
+					Query:    config.Header{"limit": {"10"}},
+					Auth: &config.Auth{
+						Bearer: &config.BearerAuth{Token: "${token}"},
+					},
+				}),
+				"Post": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Body": Equal(map[string]any{"name": "a"}),
+					"Auth": BeNil(),
+				})),
+			})),
+		})))
+	})
+
+	It("skips multipart form data", func() {
+		e, err := collection.ParseInsomnia([]byte(`{
+  "_type": "export",
+  "__export_format": 4,
+  "resources": [
+    {"_id": "wrk_1", "_type": "workspace", "name": "Example API"},
+    {"_id": "req_1", "parentId": "wrk_1", "_type": "request", "name": "Upload", "method": "POST",
+     "url": "https://example.com/upload",
+     "body": {"mimeType": "multipart/form-data", "params": [{"name": "file", "type": "file", "fileName": "a.txt"}]}}
+  ]
+}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e.ToConfig("example").Resources[0].Post).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Form": BeNil(),
+			"Body": BeNil(),
+		})))
+	})
+
+	DescribeTable("detects the format", func(data string, expected bool) {
+		Expect(collection.IsInsomnia([]byte(data))).To(Equal(expected))
+	},
+		Entry("export", `{"_type": "export", "__export_format": 4, "resources": []}`, true),
+		Entry("other version", `{"_type": "export", "__export_format": 3}`, false),
+		Entry("other JSON", `{"info": {}}`, false),
+	)
+})
@@ -0,0 +1,399 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+)
+
+// PostmanSchema is the schema of Postman v2.1 collections
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+var (
+	postmanVar     = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+	postmanPathVar = regexp.MustCompile(`(^|/):([A-Za-z_][A-Za-z0-9_-]*)`)
+)
+
+// Postman is a Postman v2.1 collection
+type Postman struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []*PostmanItem    `json:"item"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+	Auth     *PostmanAuth      `json:"auth,omitempty"`
+}
+
+type PostmanInfo struct {
+	Name        string             `json:"name"`
+	PostmanID   string             `json:"_postman_id,omitempty"`
+	Description PostmanDescription `json:"description,omitempty"`
+	Schema      string             `json:"schema"`
+}
+
+// PostmanItem is either a folder, which contains items, or a request
+type PostmanItem struct {
+	Name        string             `json:"name"`
+	Description PostmanDescription `json:"description,omitempty"`
+	Item        []*PostmanItem     `json:"item,omitempty"`
+	Request     *PostmanRequest    `json:"request,omitempty"`
+	Variable    []PostmanVariable  `json:"variable,omitempty"`
+	Auth        *PostmanAuth       `json:"auth,omitempty"`
+}
+
+type PostmanRequest struct {
+	Method      string             `json:"method"`
+	Header      []PostmanKeyValue  `json:"header,omitempty"`
+	URL         PostmanURL         `json:"url"`
+	Body        *PostmanBody       `json:"body,omitempty"`
+	Auth        *PostmanAuth       `json:"auth,omitempty"`
+	Description PostmanDescription `json:"description,omitempty"`
+}
+
+// PostmanURL is the URL of a request, which is either a string or an
+// object that contains the parts of the URL
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol,omitempty"`
+	Host     []string          `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
+	Path     []string          `json:"path,omitempty"`
+	Query    []PostmanKeyValue `json:"query,omitempty"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+type PostmanKeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type PostmanVariable struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+type PostmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	URLEncoded []PostmanKeyValue `json:"urlencoded,omitempty"`
+	FormData   []PostmanKeyValue `json:"formdata,omitempty"`
+	GraphQL    *PostmanGraphQL   `json:"graphql,omitempty"`
+	Options    *PostmanOptions   `json:"options,omitempty"`
+}
+
+type PostmanGraphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables,omitempty"`
+}
+
+type PostmanOptions struct {
+	Raw struct {
+		Language string `json:"language,omitempty"`
+	} `json:"raw"`
+}
+
+// PostmanAuth provides the auth of a collection, folder, or request. The
+// parameters of each type of auth are stored in the field named by the
+// type.
+type PostmanAuth struct {
+	Type   string            `json:"type"`
+	Basic  []PostmanKeyValue `json:"basic,omitempty"`
+	Bearer []PostmanKeyValue `json:"bearer,omitempty"`
+	APIKey []PostmanKeyValue `json:"apikey,omitempty"`
+	Digest []PostmanKeyValue `json:"digest,omitempty"`
+	OAuth2 []PostmanKeyValue `json:"oauth2,omitempty"`
+	AWSV4  []PostmanKeyValue `json:"awsv4,omitempty"`
+}
+
+// PostmanDescription is a description, which is either a string or an
+// object that contains the content
+type PostmanDescription string
+
+// PostmanEnvironment is an exported Postman environment
+type PostmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []PostmanKeyValue `json:"values"`
+	Scope  string            `json:"_postman_variable_scope,omitempty"`
+}
+
+// ParsePostman parses a Postman v2.1 collection
+func ParsePostman(data []byte) (*Postman, error) {
+	var c Postman
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if !IsPostman(data) {
+		return nil, errors.New("not a Postman v2.1 collection")
+	}
+	return &c, nil
+}
+
+// IsPostman tests whether the data is a Postman v2 collection
+func IsPostman(data []byte) bool {
+	var c struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+	}
+	return json.Unmarshal(data, &c) == nil &&
+		strings.Contains(c.Info.Schema, "schema.getpostman.com/json/collection/v2")
+}
+
+// ParsePostmanEnvironment parses an exported Postman environment
+func ParsePostmanEnvironment(data []byte) (*PostmanEnvironment, error) {
+	var e PostmanEnvironment
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	if !IsPostmanEnvironment(data) {
+		return nil, errors.New("not a Postman environment")
+	}
+	return &e, nil
+}
+
+// IsPostmanEnvironment tests whether the data is an exported Postman
+// environment
+func IsPostmanEnvironment(data []byte) bool {
+	var e PostmanEnvironment
+	return json.Unmarshal(data, &e) == nil && e.Scope == "environment"
+}
+
+// ToConfig converts the collection to the service with the given name.
+// Folders become resources, requests become endpoints, and collection
+// variables become vars.
+func (c *Postman) ToConfig(name string) *config.Service {
+	root := &folder{
+		name:        c.Info.Name,
+		description: string(c.Info.Description),
+		vars:        postmanVars(c.Variable),
+		auth:        c.Auth.toConfig(),
+	}
+	postmanItems(root, c.Item)
+	return root.toConfig(name)
+}
+
+// ToConfig converts the environment to the service with the given name.
+// The environment becomes an entry in the var set named env.
+func (e *PostmanEnvironment) ToConfig(name string) *config.Service {
+	vars := map[string]any{}
+	for _, kv := range e.Values {
+		if !kv.Disabled {
+			vars[kv.Key] = kv.Value
+		}
+	}
+	return &config.Service{
+		Name: name,
+		VarSets: []config.VarSet{
+			{
+				Name: "env",
+				Vars: map[string]map[string]any{
+					envName(e.Name): vars,
+				},
+			},
+		},
+	}
+}
+
+func postmanItems(f *folder, items []*PostmanItem) {
+	for _, item := range items {
+		if item.Request == nil {
+			child := &folder{
+				name:        item.Name,
+				description: string(item.Description),
+				vars:        postmanVars(item.Variable),
+				auth:        item.Auth.toConfig(),
+			}
+			postmanItems(child, item.Item)
+			f.folders = append(f.folders, child)
+			continue
+		}
+
+		f.requests = append(f.requests, item.Request.toRequest(item))
+	}
+}
+
+func (p *PostmanRequest) toRequest(item *PostmanItem) *request {
+	r := &request{
+		name:        item.Name,
+		description: string(item.Description),
+		method:      strings.ToUpper(p.Method),
+		url:         p.URL.template(),
+		headers:     toHeader(postmanKeyValues(p.Header), postmanExpr),
+		query:       toHeader(postmanKeyValues(p.URL.Query), postmanExpr),
+		auth:        p.Auth.toConfig(),
+	}
+	if r.method == "" {
+		r.method = "GET"
+	}
+	if r.description == "" {
+		r.description = string(p.Description)
+	}
+
+	if b := p.Body; b != nil {
+		switch b.Mode {
+		case "raw":
+			r.body, r.rawBody = decodeBody(postmanExpr(b.Raw))
+		case "urlencoded":
+			r.form = config.Form(toHeader(postmanKeyValues(b.URLEncoded), postmanExpr))
+		case "formdata":
+			warnMultipart(r.method, r.url)
+		case "graphql":
+			if b.GraphQL != nil {
+				body := map[string]any{"query": b.GraphQL.Query}
+				var variables any
+				if json.Unmarshal([]byte(b.GraphQL.Variables), &variables) == nil {
+					body["variables"] = variables
+				}
+				r.body = body
+			}
+		}
+	}
+	return r
+}
+
+// template gets the URL without its query using URI template syntax
+func (u *PostmanURL) template() string {
+	raw := u.Raw
+	if raw == "" {
+		raw = u.Protocol
+		if raw != "" {
+			raw += "://"
+		}
+		raw += strings.Join(u.Host, ".")
+		if u.Port != "" {
+			raw += ":" + u.Port
+		}
+		if len(u.Path) > 0 {
+			raw += "/" + strings.Join(u.Path, "/")
+		}
+	}
+	raw, _, _ = strings.Cut(raw, "?")
+	raw = postmanPathVar.ReplaceAllString(raw, "$1{$2}")
+	return postmanVar.ReplaceAllString(raw, "{$1}")
+}
+
+func (u *PostmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = PostmanURL{Raw: raw}
+		return nil
+	}
+	type plain PostmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+func (d *PostmanDescription) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*d = PostmanDescription(s)
+		return nil
+	}
+	var v struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*d = PostmanDescription(v.Content)
+	return nil
+}
+
+func (a *PostmanAuth) toConfig() *config.Auth {
+	if a == nil {
+		return nil
+	}
+	switch a.Type {
+	case "basic":
+		p := postmanAuthParams(a.Basic)
+		return &config.Auth{
+			Basic: &config.BasicAuth{User: p["username"], Password: p["password"]},
+		}
+	case "bearer":
+		p := postmanAuthParams(a.Bearer)
+		return &config.Auth{
+			Bearer: &config.BearerAuth{Token: p["token"]},
+		}
+	case "digest":
+		p := postmanAuthParams(a.Digest)
+		return &config.Auth{
+			Digest: &config.DigestAuth{User: p["username"], Password: p["password"]},
+		}
+	case "apikey":
+		p := postmanAuthParams(a.APIKey)
+		auth := &config.APIKeyAuth{Key: p["value"]}
+		if p["in"] == "query" {
+			auth.Query = p["key"]
+		} else {
+			auth.Header = p["key"]
+		}
+		return &config.Auth{APIKey: auth}
+	case "oauth2":
+		p := postmanAuthParams(a.OAuth2)
+		if p["grant_type"] != "client_credentials" {
+			return nil
+		}
+		return &config.Auth{
+			OAuth2: &config.OAuth2Auth{
+				TokenURL:     p["accessTokenUrl"],
+				ClientID:     p["clientId"],
+				ClientSecret: p["clientSecret"],
+				Scopes:       strings.Fields(p["scope"]),
+			},
+		}
+	case "awsv4":
+		p := postmanAuthParams(a.AWSV4)
+		return &config.Auth{
+			AWSSigV4: &config.AWSSigV4Auth{
+				Region:          p["region"],
+				Service:         p["service"],
+				AccessKeyID:     p["accessKey"],
+				SecretAccessKey: p["secretKey"],
+				SessionToken:    p["sessionToken"],
+			},
+		}
+	}
+	return nil
+}
+
+func postmanAuthParams(values []PostmanKeyValue) map[string]string {
+	res := map[string]string{}
+	for _, kv := range values {
+		res[kv.Key] = postmanExpr(kv.Value)
+	}
+	return res
+}
+
+func postmanKeyValues(values []PostmanKeyValue) []keyValue {
+	res := make([]keyValue, 0, len(values))
+	for _, kv := range values {
+		if kv.Type == "file" {
+			continue
+		}
+		res = append(res, keyValue{kv.Key, kv.Value, kv.Disabled})
+	}
+	return res
+}
+
+func postmanVars(values []PostmanVariable) map[string]any {
+	var res map[string]any
+	for _, v := range values {
+		if res == nil {
+			res = map[string]any{}
+		}
+		res[v.Key] = v.Value
+	}
+	return res
+}
+
+// postmanExpr converts the Postman variables in s to Pastiche expressions
+func postmanExpr(s string) string {
+	return postmanVar.ReplaceAllString(s, "$${$1}")
+}
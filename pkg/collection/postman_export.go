@@ -0,0 +1,301 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+var (
+	pasticheExpr = regexp.MustCompile(`\$\{([^{}]+)\}`)
+)
+
+// baseURLVarName is the name of the collection variable which provides
+// the base URL
+const baseURLVarName = "baseUrl"
+
+// PostmanFromModel converts the service to a Postman v2.1 collection.
+// Resources which contain other resources become folders, and endpoints
+// become requests. The base URL of the first server is provided by the
+// baseUrl collection variable.
+func PostmanFromModel(svc *model.Service) *Postman {
+	c := &Postman{
+		Info: PostmanInfo{
+			Name:        cmp.Or(svc.Title, svc.Name),
+			Description: PostmanDescription(svc.Description),
+			Schema:      PostmanSchema,
+		},
+		Auth: postmanAuth(svc.Auth),
+	}
+	for _, k := range slices.Sorted(maps.Keys(svc.Vars)) {
+		c.Variable = append(c.Variable, PostmanVariable{Key: k, Value: svc.Vars[k]})
+	}
+	if len(svc.Servers) > 0 {
+		c.Variable = append(c.Variable, PostmanVariable{
+			Key:   baseURLVarName,
+			Value: postmanTemplate(svc.Servers[0].BaseURL),
+		})
+	}
+
+	if svc.Resource != nil {
+		c.Item = postmanResource(svc.Resource, nil)
+	}
+	return c
+}
+
+func postmanResource(r *model.Resource, lineage []*model.Resource) []*PostmanItem {
+	lineage = append(lineage, r)
+
+	var items []*PostmanItem
+	if !r.HasImplicitEndpoint() {
+		for _, ep := range r.Endpoints {
+			items = append(items, postmanRequest(lineage, ep))
+		}
+	}
+	for _, child := range r.Resources {
+		childItems := postmanResource(child, lineage)
+		if len(child.Resources) == 0 {
+			items = append(items, childItems...)
+			continue
+		}
+		items = append(items, &PostmanItem{
+			Name:        cmp.Or(child.Title, child.Name),
+			Description: PostmanDescription(child.Description),
+			Item:        childItems,
+		})
+	}
+	return items
+}
+
+func postmanRequest(lineage []*model.Resource, ep *model.Endpoint) *PostmanItem {
+	headers := map[string][]string{}
+	query := map[string][]string{}
+	var (
+		auth    model.Auth
+		body    any
+		rawBody any
+	)
+	for _, r := range lineage {
+		headers = model.ReduceHeader(headers, r.Headers)
+		query = model.ReduceHeader(query, r.Query)
+		if r.Auth != nil {
+			auth = r.Auth
+		}
+		if r.Body != nil {
+			body = r.Body
+		}
+		if r.RawBody != nil {
+			rawBody = r.RawBody
+		}
+	}
+	headers = model.ReduceHeader(headers, ep.Headers)
+	query = model.ReduceHeader(query, ep.Query)
+	if ep.Auth != nil {
+		auth = ep.Auth
+	}
+	if ep.Body != nil {
+		body = ep.Body
+	}
+	if ep.RawBody != nil {
+		rawBody = ep.RawBody
+	}
+
+	u := postmanURL(lineage)
+	u.Query = append(u.Query, postmanKeyValuesFrom(query)...)
+	u.Raw = rawURL(u)
+
+	req := &PostmanRequest{
+		Method: strings.ToUpper(cmp.Or(ep.Method, "GET")),
+		Header: postmanKeyValuesFrom(headers),
+		URL:    u,
+		Auth:   postmanAuth(auth),
+	}
+
+	switch {
+	case body != nil:
+		data, err := json.MarshalIndent(body, "", "  ")
+		if err == nil {
+			req.Body = &PostmanBody{Mode: "raw", Raw: postmanValue(string(data))}
+			req.Body.Options = &PostmanOptions{}
+			req.Body.Options.Raw.Language = "json"
+		}
+	case rawBody != nil:
+		req.Body = &PostmanBody{Mode: "raw", Raw: postmanValue(fmt.Sprint(rawBody))}
+	case len(ep.Form) > 0:
+		req.Body = &PostmanBody{Mode: "urlencoded", URLEncoded: postmanKeyValuesFrom(ep.Form)}
+	}
+
+	return &PostmanItem{
+		Name:        cmp.Or(ep.Title, ep.Name, req.Method+" "+u.Raw),
+		Description: PostmanDescription(ep.Description),
+		Request:     req,
+	}
+}
+
+// postmanURL joins the URI templates of the lineage into the path of a URL
+// relative to the base URL. Template expressions which expand into the
+// query string become query parameters; other expressions become path
+// variables.
+func postmanURL(lineage []*model.Resource) PostmanURL {
+	var segments []string
+	for _, r := range lineage {
+		if r.URITemplate != nil {
+			segments = append(segments, r.URITemplate.String())
+		}
+	}
+	template := strings.TrimPrefix(path.Clean("/"+path.Join(segments...)), "/")
+
+	u := PostmanURL{Host: []string{"{{" + baseURLVarName + "}}"}}
+	p := model.ReplaceTemplateExprs(template, func(_, op string, names []string) string {
+		var res []string
+		switch op {
+		case "?", "&":
+			for _, name := range names {
+				u.Query = append(u.Query, PostmanKeyValue{Key: name, Value: "{{" + name + "}}"})
+			}
+			return ""
+		case "+", "#":
+			for _, name := range names {
+				res = append(res, "{{"+name+"}}")
+			}
+			return strings.Join(res, "")
+		}
+
+		var prefix string
+		if op == "/" || op == "." {
+			prefix = op
+		}
+		for _, name := range names {
+			u.Variable = append(u.Variable, PostmanVariable{Key: name, Value: ""})
+			res = append(res, prefix+":"+name)
+		}
+		return strings.Join(res, "")
+	})
+	for s := range strings.SplitSeq(p, "/") {
+		if s != "" {
+			u.Path = append(u.Path, s)
+		}
+	}
+	return u
+}
+
+func rawURL(u PostmanURL) string {
+	raw := strings.Join(u.Host, ".")
+	if len(u.Path) > 0 {
+		raw += "/" + strings.Join(u.Path, "/")
+	}
+	for i, q := range u.Query {
+		sep := "&"
+		if i == 0 {
+			sep = "?"
+		}
+		raw += sep + q.Key + "=" + q.Value
+	}
+	return raw
+}
+
+func postmanAuth(a model.Auth) *PostmanAuth {
+	switch auth := a.(type) {
+	case *model.NoAuth:
+		return &PostmanAuth{Type: "noauth"}
+	case *model.BasicAuth:
+		return &PostmanAuth{
+			Type: "basic",
+			Basic: []PostmanKeyValue{
+				postmanParam("username", auth.User),
+				postmanParam("password", auth.Password),
+			},
+		}
+	case *model.BearerAuth:
+		return &PostmanAuth{
+			Type:   "bearer",
+			Bearer: []PostmanKeyValue{postmanParam("token", auth.Token)},
+		}
+	case *model.DigestAuth:
+		return &PostmanAuth{
+			Type: "digest",
+			Digest: []PostmanKeyValue{
+				postmanParam("username", auth.User),
+				postmanParam("password", auth.Password),
+			},
+		}
+	case *model.APIKeyAuth:
+		in, key := "header", auth.HeaderName()
+		if auth.Query != "" {
+			in, key = "query", auth.Query
+		}
+		return &PostmanAuth{
+			Type: "apikey",
+			APIKey: []PostmanKeyValue{
+				postmanParam("key", key),
+				postmanParam("value", auth.Key),
+				postmanParam("in", in),
+			},
+		}
+	case *model.OAuth2Auth:
+		return &PostmanAuth{
+			Type: "oauth2",
+			OAuth2: []PostmanKeyValue{
+				postmanParam("grant_type", "client_credentials"),
+				postmanParam("accessTokenUrl", auth.TokenURL),
+				postmanParam("clientId", auth.ClientID),
+				postmanParam("clientSecret", auth.ClientSecret),
+				postmanParam("scope", strings.Join(auth.Scopes, " ")),
+			},
+		}
+	case *model.AWSSigV4Auth:
+		return &PostmanAuth{
+			Type: "awsv4",
+			AWSV4: []PostmanKeyValue{
+				postmanParam("region", auth.Region),
+				postmanParam("service", auth.Service),
+				postmanParam("accessKey", auth.AccessKeyID),
+				postmanParam("secretKey", auth.SecretAccessKey),
+				postmanParam("sessionToken", auth.SessionToken),
+			},
+		}
+	}
+	return nil
+}
+
+func postmanParam(key, value string) PostmanKeyValue {
+	return PostmanKeyValue{Key: key, Value: postmanValue(value), Type: "string"}
+}
+
+func postmanKeyValuesFrom(m map[string][]string) []PostmanKeyValue {
+	var res []PostmanKeyValue
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		for _, v := range m[k] {
+			res = append(res, PostmanKeyValue{Key: k, Value: postmanValue(v)})
+		}
+	}
+	return res
+}
+
+// postmanValue converts the Pastiche expressions in s to Postman variables
+func postmanValue(s string) string {
+	return pasticheExpr.ReplaceAllString(s, "{{$1}}")
+}
+
+// postmanTemplate converts the URI template expressions in s to Postman
+// variables
+func postmanTemplate(s string) string {
+	return model.ReplaceTemplateExprs(s, func(_, _ string, names []string) string {
+		var res []string
+		for _, name := range names {
+			res = append(res, "{{"+name+"}}")
+		}
+		return strings.Join(res, "")
+	})
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection_test
+
+import (
+	"github.com/Carbonfrost/pastiche/pkg/collection"
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("PostmanFromModel", func() {
+
+	var subject = func() *collection.Postman {
+		mo := model.New(&config.File{
+			Service: &config.Service{
+				Name: "example",
+				Metadata: config.Metadata{
+					Title: "Example API",
+				},
+				Servers: []config.Server{
+					{Name: "default", BaseURL: "https://api.example.com"},
+				},
+				Vars: map[string]any{"limit": 10},
+				Auth: &config.Auth{
+					Bearer: &config.BearerAuth{Token: "${token}"},
+				},
+				Resources: []config.Resource{
+					{
+						Name: "users",
+						URI:  "users{?limit}",
+						Get: &config.Endpoint{
+							Name:    "listUsers",
+							Headers: config.Header{"Accept": {"application/json"}},
+						},
+						Post: &config.Endpoint{
+							Name: "createUser",
+							Body: map[string]any{"name": "${name}"},
+						},
+						Resources: []config.Resource{
+							{
+								Name:    "user",
+								URI:     "{id}",
+								Headers: config.Header{"X-Trace": {"a", "b"}},
+								Delete: &config.Endpoint{
+									Name:    "deleteUser",
+									Headers: config.Header{"-X-Trace": {"a"}},
+									Form:    config.Form{"reason": {"spam"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		svc, _ := mo.Service("example")
+		return collection.PostmanFromModel(svc)
+	}
+
+	It("converts the info and variables", func() {
+		c := subject()
+		Expect(c.Info.Name).To(Equal("Example API"))
+		Expect(c.Info.Schema).To(Equal(collection.PostmanSchema))
+		Expect(c.Variable).To(Equal([]collection.PostmanVariable{
+			{Key: "limit", Value: 10},
+			{Key: "baseUrl", Value: "https://api.example.com"},
+		}))
+		Expect(c.Auth).To(Equal(&collection.PostmanAuth{
+			Type:   "bearer",
+			Bearer: []collection.PostmanKeyValue{{Key: "token", Value: "{{token}}", Type: "string"}},
+		}))
+	})
+
+	It("converts resources with children to folders", func() {
+		c := subject()
+		Expect(c.Item).To(HaveLen(1))
+		Expect(c.Item[0].Name).To(Equal("users"))
+		Expect(c.Item[0].Item).To(HaveLen(3))
+	})
+
+	It("converts endpoints to requests", func() {
+		items := subject().Item[0].Item
+		Expect(items[0]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("listUsers"),
+			"Request": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Method": Equal("GET"),
+				"Header": Equal([]collection.PostmanKeyValue{{Key: "Accept", Value: "application/json"}}),
+				"URL": Equal(collection.PostmanURL{
+					Raw:   "{{baseUrl}}/users?limit={{limit}}",
+					Host:  []string{"{{baseUrl}}"},
+					Path:  []string{"users"},
+					Query: []collection.PostmanKeyValue{{Key: "limit", Value: "{{limit}}"}},
+				}),
+			})),
+		})))
+		Expect(items[1].Request.Body.Raw).To(MatchJSON(`{"name": "{{name}}"}`))
+		Expect(items[1].Request.Body.Options.Raw.Language).To(Equal("json"))
+	})
+
+	It("converts path variables", func() {
+		req := subject().Item[0].Item[2].Request
+		Expect(req.Method).To(Equal("DELETE"))
+		Expect(req.URL.Path).To(Equal([]string{"users", ":id"}))
+		Expect(req.URL.Variable).To(Equal([]collection.PostmanVariable{{Key: "id", Value: ""}}))
+		Expect(req.Body).To(Equal(&collection.PostmanBody{
+			Mode:       "urlencoded",
+			URLEncoded: []collection.PostmanKeyValue{{Key: "reason", Value: "spam"}},
+		}))
+	})
+
+	It("removes only the listed header values", func() {
+		req := subject().Item[0].Item[2].Request
+		Expect(req.Header).To(Equal([]collection.PostmanKeyValue{{Key: "X-Trace", Value: "b"}}))
+	})
+
+	It("round trips through import", func() {
+		svc := subject().ToConfig("example")
+		Expect(svc.Servers).To(Equal([]config.Server{
+			{Name: "default", BaseURL: "{+baseUrl}"},
+		}))
+		Expect(svc.Resources).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("users"),
+			"Resources": ContainElement(MatchFields(IgnoreExtras, Fields{
+				"URI":    Equal("users/{id}"),
+				"Delete": Not(BeNil()),
+			})),
+		})))
+	})
+})
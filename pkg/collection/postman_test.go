@@ -0,0 +1,145 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package collection_test
+
+import (
+	"os"
+
+	"github.com/Carbonfrost/pastiche/pkg/collection"
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Postman", func() {
+
+	Describe("ToConfig", func() {
+
+		var subject = func() *config.Service {
+			data, err := os.ReadFile("testdata/postman.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			c, err := collection.ParsePostman(data)
+			Expect(err).NotTo(HaveOccurred())
+			return c.ToConfig("example")
+		}
+
+		It("converts the info, variables, and auth", func() {
+			svc := subject()
+			Expect(svc.Name).To(Equal("example"))
+			Expect(svc.Title).To(Equal("Example API"))
+			Expect(svc.Description).To(Equal("An example collection"))
+			Expect(svc.Vars).To(Equal(map[string]any{
+				"baseUrl": "https://api.example.com",
+				"token":   "secret",
+			}))
+			Expect(svc.Auth).To(Equal(&config.Auth{
+				Bearer: &config.BearerAuth{Token: "${token}"},
+			}))
+		})
+
+		It("converts each origin to a server", func() {
+			Expect(subject().Servers).To(Equal([]config.Server{
+				{Name: "default", BaseURL: "{+baseUrl}"},
+				{Name: "server2", BaseURL: "https://auth.example.com"},
+			}))
+		})
+
+		It("converts folders to resources", func() {
+			Expect(subject().Resources[0]).To(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("Users"),
+				"Metadata": Equal(config.Metadata{
+					Title:       "Users",
+					Description: "Manage users",
+				}),
+				"Resources": HaveLen(2),
+			}))
+		})
+
+		It("converts requests with the same URL to endpoints of one resource", func() {
+			users := subject().Resources[0].Resources[0]
+			Expect(users.URI).To(Equal("users"))
+			Expect(users.Get).To(Equal(&config.Endpoint{
+				Name:     "List-users",
+				Metadata: config.Metadata{Title: "List users"},
+				Headers:  config.Header{"Accept": {"application/json"}},
+				Query:    config.Header{"limit": {"10"}},
+			}))
+			Expect(users.Post).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Headers": Equal(config.Header{"Content-Type": {"application/json"}}),
+				"Body":    Equal(map[string]any{"name": "${name}"}),
+			})))
+		})
+
+		It("converts path variables", func() {
+			Expect(subject().Resources[0].Resources[1].URI).To(Equal("users/{id}"))
+		})
+
+		It("converts the form and auth of the request", func() {
+			Expect(subject().Resources[1]).To(MatchFields(IgnoreExtras, Fields{
+				"URI": Equal("login"),
+				"Post": PointTo(MatchFields(IgnoreExtras, Fields{
+					"Form": Equal(config.Form{"grant_type": {"password"}}),
+					"Auth": Equal(&config.Auth{
+						Basic: &config.BasicAuth{User: "${user}", Password: "${password}"},
+					}),
+				})),
+			}))
+		})
+	})
+
+	It("skips multipart form data", func() {
+		c, err := collection.ParsePostman([]byte(`{
+  "info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "item": [{
+    "name": "Upload",
+    "request": {
+      "method": "POST",
+      "url": "https://example.com/upload",
+      "body": {"mode": "formdata", "formdata": [{"key": "file", "type": "file", "src": "a.txt"}]}
+    }
+  }]
+}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.ToConfig("example").Resources[0].Post).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Form": BeNil(),
+			"Body": BeNil(),
+		})))
+	})
+
+	Describe("environment", func() {
+
+		It("converts to a var set", func() {
+			data, err := os.ReadFile("testdata/postman_environment.json")
+			Expect(err).NotTo(HaveOccurred())
+
+			env, err := collection.ParsePostmanEnvironment(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.ToConfig("example").VarSets).To(Equal([]config.VarSet{
+				{
+					Name: "env",
+					Vars: map[string]map[string]any{
+						"staging": {
+							"baseUrl": "https://staging.example.com",
+							"token":   "staging-token",
+						},
+					},
+				},
+			}))
+		})
+	})
+
+	DescribeTable("detects the format", func(data string, postman, environment bool) {
+		Expect(collection.IsPostman([]byte(data))).To(Equal(postman))
+		Expect(collection.IsPostmanEnvironment([]byte(data))).To(Equal(environment))
+	},
+		Entry("collection", `{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"}}`, true, false),
+		Entry("environment", `{"name": "a", "values": [], "_postman_variable_scope": "environment"}`, false, true),
+		Entry("other JSON", `{"openapi": "3.1.0"}`, false, false),
+		Entry("not JSON", `curl https://example.com`, false, false),
+	)
+})
@@ -4,6 +4,16 @@
 
 package config
 
+import (
+	"iter"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var nonIdentifierChar = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
 type File struct {
 	Schema string `json:"$schema,omitempty"`
 
@@ -73,6 +83,7 @@ type Resource struct {
 	Head      *Endpoint      `json:"head,omitempty"`
 	Trace     *Endpoint      `json:"trace,omitempty"`
 	Patch     *Endpoint      `json:"patch,omitempty"`
+	Methods   Methods        `json:"methods,omitempty"`
 	Body      any            `json:"body,omitempty"`
 	RawBody   any            `json:"rawBody,omitempty"`
 	Vars      map[string]any `json:"vars,omitempty"`
@@ -99,13 +110,35 @@ type HTTPClient struct {
 }
 
 type GRPCClient struct {
-	DisableReflection bool   `json:"disableReflection,omitzero"`
-	ProtoSet          string `json:"protoset,omitempty"`
-	Plaintext         bool   `json:"plaintext,omitzero"`
+	DisableReflection  bool     `json:"disableReflection,omitzero"`
+	ProtoSet           string   `json:"protoset,omitempty"`
+	PreferProtoSet     bool     `json:"preferProtoset,omitzero"`
+	Plaintext          bool     `json:"plaintext,omitzero"`
+	Authority          string   `json:"authority,omitempty"`
+	ServerName         string   `json:"serverName,omitempty"`
+	DialTimeout        Duration `json:"dialTimeout,omitzero"`
+	Deadline           Duration `json:"deadline,omitzero"`
+	Keepalive          Duration `json:"keepalive,omitzero"`
+	MaxRecvMessageSize int      `json:"maxRecvMessageSize,omitzero"`
+	MaxSendMessageSize int      `json:"maxSendMessageSize,omitzero"`
+	Protocol           string   `json:"protocol,omitempty"`
+	Encoding           string   `json:"encoding,omitempty"`
 }
 
 type Auth struct {
-	Basic *BasicAuth `json:"basic,omitempty"`
+	Basic    *BasicAuth    `json:"basic,omitempty"`
+	Bearer   *BearerAuth   `json:"bearer,omitempty"`
+	APIKey   *APIKeyAuth   `json:"apiKey,omitempty"`
+	OAuth2   *OAuth2Auth   `json:"oauth2,omitempty"`
+	Digest   *DigestAuth   `json:"digest,omitempty"`
+	AWSSigV4 *AWSSigV4Auth `json:"awsSigV4,omitempty"`
+
+	// None disables the auth which would otherwise be inherited
+	None bool `json:"none,omitempty"`
+
+	FromSpec string   `json:"fromSpec,omitempty"`
+	Extract  *Extract `json:"extract,omitempty"`
+	TTL      Duration `json:"ttl,omitzero"`
 }
 
 type BasicAuth struct {
@@ -113,6 +146,42 @@ type BasicAuth struct {
 	Password string `json:"password,omitempty"`
 }
 
+type BearerAuth struct {
+	Token string `json:"token,omitempty"`
+}
+
+type APIKeyAuth struct {
+	Key    string `json:"key,omitempty"`
+	Header string `json:"header,omitempty"`
+	Query  string `json:"query,omitempty"`
+}
+
+type OAuth2Auth struct {
+	TokenURL     string   `json:"tokenUrl,omitempty"`
+	ClientID     string   `json:"clientId,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	RefreshToken string   `json:"refreshToken,omitempty"`
+}
+
+type DigestAuth struct {
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type AWSSigV4Auth struct {
+	Region          string `json:"region,omitempty"`
+	Service         string `json:"service,omitempty"`
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+}
+
+type Extract struct {
+	JMESPath *JMESPathOutput `json:"jmespath,omitempty"`
+	Dig      *DigOutput      `json:"dig,omitempty"`
+}
+
 type Output struct {
 	Name string `json:"name,omitempty"`
 
@@ -161,15 +230,29 @@ type Endpoint struct {
 	Source string `json:"source,omitempty"`
 
 	Metadata
-	Headers Header         `json:"headers,omitempty"`
-	Query   Header         `json:"query,omitempty"`
-	Form    Form           `json:"form,omitempty"`
-	Body    any            `json:"body,omitempty"`
-	RawBody any            `json:"rawBody,omitempty"`
-	Vars    map[string]any `json:"vars,omitempty"`
-	Auth    *Auth          `json:"auth,omitempty"`
-	Output  []Output       `json:"output,omitempty"`
-	VarSets []VarSet       `json:"varSets,omitempty"`
+	Headers  Header         `json:"headers,omitempty"`
+	Query    Header         `json:"query,omitempty"`
+	Form     Form           `json:"form,omitempty"`
+	Body     any            `json:"body,omitempty"`
+	RawBody  any            `json:"rawBody,omitempty"`
+	BodyFile string         `json:"bodyFile,omitempty"`
+	Vars     map[string]any `json:"vars,omitempty"`
+	Auth     *Auth          `json:"auth,omitempty"`
+	Output   []Output       `json:"output,omitempty"`
+	VarSets  []VarSet       `json:"varSets,omitempty"`
+	Expect   *Expect        `json:"expect,omitempty"`
+}
+
+// Methods provides endpoints keyed by their method, which allows methods
+// other than the ones with dedicated fields, such as PROPFIND or QUERY
+type Methods map[string]*Endpoint
+
+type Expect struct {
+	Status     []int    `json:"status,omitempty"`
+	Headers    Header   `json:"headers,omitempty"`
+	JMESPath   []string `json:"jmespath,omitempty"`
+	XPath      []string `json:"xpath,omitempty"`
+	MaxLatency Duration `json:"maxLatency,omitzero"`
 }
 
 type Link struct {
@@ -196,14 +279,23 @@ type Step struct {
 	Name string `json:"name,omitempty"`
 
 	Metadata
-	Method  string         `json:"method,omitempty"`
-	Headers Header         `json:"headers,omitempty"`
-	Form    Form           `json:"form,omitempty"`
-	Body    any            `json:"body,omitempty"`
-	RawBody any            `json:"rawBody,omitempty"`
-	Vars    map[string]any `json:"vars,omitempty"`
-	Spec    string         `json:"spec,omitempty"`
-	URL     string         `json:"url,omitempty"`
+	Method   string         `json:"method,omitempty"`
+	Headers  Header         `json:"headers,omitempty"`
+	Form     Form           `json:"form,omitempty"`
+	Body     any            `json:"body,omitempty"`
+	RawBody  any            `json:"rawBody,omitempty"`
+	Vars     map[string]any `json:"vars,omitempty"`
+	Spec     string         `json:"spec,omitempty"`
+	URL      string         `json:"url,omitempty"`
+	Captures []Capture      `json:"captures,omitempty"`
+	Expect   *Expect        `json:"expect,omitempty"`
+}
+
+type Capture struct {
+	Name string `json:"name"`
+
+	JMESPath *JMESPathOutput `json:"jmespath,omitempty"`
+	Dig      *DigOutput      `json:"dig,omitempty"`
 }
 
 func (f *File) Name() string {
@@ -213,3 +305,97 @@ func (f *File) Name() string {
 func (f *File) SetName(name string) {
 	f.name = name
 }
+
+// Identifier converts s into a valid name by replacing runs of characters
+// which are not allowed with dashes. This is used to derive the names of
+// resources and endpoints from other formats.
+func Identifier(s string) string {
+	s = strings.Trim(nonIdentifierChar.ReplaceAllString(s, "-"), "-")
+	if s != "" && s[0] >= '0' && s[0] <= '9' {
+		s = "_" + s
+	}
+	return s
+}
+
+// FindService finds the service defined by the file with the given name
+func (f *File) FindService(name string) *Service {
+	if f.Service != nil {
+		if f.Service.Name == name {
+			return f.Service
+		}
+		return nil
+	}
+	for i := range f.Services {
+		if f.Services[i].Name == name {
+			return &f.Services[i]
+		}
+	}
+	return nil
+}
+
+// Endpoints iterates the endpoints of the resource by method, including
+// the endpoints for custom methods
+func (r *Resource) Endpoints() iter.Seq2[string, *Endpoint] {
+	return func(yield func(string, *Endpoint) bool) {
+		for _, method := range []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "TRACE", "PATCH"} {
+			if ep, ok := r.Endpoint(method); ok {
+				if !yield(method, ep) {
+					return
+				}
+			}
+		}
+		for _, method := range slices.Sorted(maps.Keys(r.Methods)) {
+			if !yield(strings.ToUpper(method), r.Methods[method]) {
+				return
+			}
+		}
+	}
+}
+
+// Endpoint gets the endpoint for the method
+func (r *Resource) Endpoint(method string) (*Endpoint, bool) {
+	if ref := r.endpointRef(method); ref != nil {
+		return *ref, *ref != nil
+	}
+	for m, ep := range r.Methods {
+		if strings.EqualFold(m, method) {
+			return ep, true
+		}
+	}
+	return nil, false
+}
+
+// SetEndpoint sets the endpoint for the method. Methods which don't have a
+// dedicated field are stored in Methods.
+func (r *Resource) SetEndpoint(method string, ep *Endpoint) {
+	if ref := r.endpointRef(method); ref != nil {
+		*ref = ep
+		return
+	}
+	if r.Methods == nil {
+		r.Methods = Methods{}
+	}
+	r.Methods[method] = ep
+}
+
+func (r *Resource) endpointRef(method string) **Endpoint {
+	switch method {
+	case "GET":
+		return &r.Get
+	case "PUT":
+		return &r.Put
+	case "POST":
+		return &r.Post
+	case "DELETE":
+		return &r.Delete
+	case "OPTIONS":
+		return &r.Options
+	case "HEAD":
+		return &r.Head
+	case "TRACE":
+		return &r.Trace
+	case "PATCH":
+		return &r.Patch
+	}
+	return nil
+}
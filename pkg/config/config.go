@@ -27,7 +27,9 @@ type sourcer struct {
 type unmarshaler func([]byte, any) error
 
 var unmarshalers = map[string]unmarshaler{
+	".http":     unmarshalHTTPFile,
 	".json":     json.Unmarshal,
+	".rest":     unmarshalHTTPFile,
 	".yaml":     unmarshalYaml,
 	".yamlvars": unmarshalYamlVarSet,
 	".yml":      unmarshalYaml,
@@ -158,10 +160,20 @@ func (s sourcer) source(basefilename string, v any) error {
 			return err
 		}
 		a.Output = fixOutputsRelative(basefilename, a.Output)
-		return s.sources(file, a.Get, a.Put, a.Post, a.Delete, a.Options, a.Head, a.Trace, a.Patch, a.Query)
+		err = s.sources(file, a.Get, a.Put, a.Post, a.Delete, a.Options, a.Head, a.Trace, a.Patch, a.Query)
+		if err != nil {
+			return err
+		}
+		for _, ep := range a.Methods {
+			if err := s.source(file, ep); err != nil {
+				return err
+			}
+		}
 
 	case *Endpoint:
-		// Nothing to do for endpoints
+		if a.BodyFile != "-" {
+			fixRelative(basefilename, &a.BodyFile)
+		}
 
 	case *Flow:
 		return sources(s, file, a.Steps)
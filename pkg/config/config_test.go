@@ -5,6 +5,7 @@ package config_test
 
 import (
 	"os"
+	"time"
 
 	"github.com/Carbonfrost/pastiche/pkg/config"
 	. "github.com/onsi/ginkgo/v2"
@@ -118,6 +119,21 @@ var _ = Describe("Config", func() {
 						})})),
 				),
 			),
+			Entry(
+				"relative body file",
+				"relative_body_file.yml",
+				haveService(
+					PointTo(
+						MatchFields(IgnoreExtras, Fields{"Resources": HaveExactElements(
+							MatchFields(IgnoreExtras, Fields{
+								"Post": PointTo(MatchFields(IgnoreExtras, Fields{"BodyFile": Equal("messages.jsonl")})),
+							}),
+							MatchFields(IgnoreExtras, Fields{
+								"Post": PointTo(MatchFields(IgnoreExtras, Fields{"BodyFile": Equal("-")})),
+							}),
+						)})),
+				),
+			),
 			Entry(
 				"preprocessed",
 				"preprocessed.yml",
@@ -212,6 +228,12 @@ var _ = Describe("Config", func() {
 								}),
 								"Spec":   Equal("@linear/gql.issue"),
 								"Method": Equal("POST"),
+								"Captures": ConsistOf(config.Capture{
+									Name: "issueId",
+									JMESPath: &config.JMESPathOutput{
+										Query: "data.issueCreate.issue.id",
+									},
+								}),
 							}),
 							MatchFields(IgnoreExtras, Fields{
 								"Name": Equal("example URL-based step"),
@@ -241,6 +263,60 @@ var _ = Describe("Config", func() {
 					},
 				)),
 			),
+			Entry(
+				"expect",
+				"expect.yml",
+				haveResource(MatchFields(IgnoreExtras, Fields{
+					"Get": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Expect": Equal(&config.Expect{
+							Status: []int{200, 201},
+							Headers: config.Header{
+								"Content-Type": {"^application/json"},
+							},
+							JMESPath:   []string{"length(items) > `0`"},
+							XPath:      []string{"//item"},
+							MaxLatency: config.Duration(500 * time.Millisecond),
+						}),
+					})),
+				})),
+			),
+			Entry(
+				"methods",
+				"methods.yml",
+				haveResource(MatchFields(IgnoreExtras, Fields{
+					"Get": Equal(&config.Endpoint{}),
+					"Methods": Equal(config.Methods{
+						"PROPFIND": {Headers: config.Header{"Depth": {"1"}}},
+						"MKCOL":    {},
+						"QUERY":    {Body: map[string]any{"q": "*"}},
+					}),
+				})),
+			),
+			Entry(
+				"auth from spec",
+				"auth_fromSpec.yml",
+				And(
+					PointTo(MatchFields(IgnoreExtras, Fields{
+						"Service": PointTo(MatchFields(IgnoreExtras, Fields{
+							"Auth": Equal(&config.Auth{
+								FromSpec: "@corp/login.session",
+								Extract: &config.Extract{
+									JMESPath: &config.JMESPathOutput{Query: "token"},
+								},
+								TTL: config.Duration(10 * time.Minute),
+							}),
+						})),
+					})),
+					haveResource(MatchFields(IgnoreExtras, Fields{
+						"Auth": Equal(&config.Auth{
+							FromSpec: "@corp/login.session",
+							Extract: &config.Extract{
+								Dig: &config.DigOutput{Query: "data.token"},
+							},
+						}),
+					})),
+				),
+			),
 		)
 
 		DescribeTable("errors",
@@ -268,6 +344,11 @@ var _ = Describe("Config", func() {
 						ContainSubstring("cannot find the file specified"),
 					)),
 			),
+			Entry(
+				"invalid duration",
+				"error_expectLatency.yml",
+				MatchError(ContainSubstring(`invalid duration "soon"`)),
+			),
 			Entry(
 				"unknown attributes",
 				"unknown-attributes.yml",
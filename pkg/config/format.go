@@ -0,0 +1,126 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ReadFile reads the given file from the file system without resolving
+// the files that it sources
+func ReadFile(f fs.FS, filename string) (*File, error) {
+	unmarshal, ok := unmarshalers[filepath.Ext(filename)]
+	if !ok {
+		return nil, fmt.Errorf("read file %s: %w", filename, ErrUnsupportedFileFormat)
+	}
+
+	data, err := fs.ReadFile(f, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(File)
+	result.SetName(filename)
+	if err := unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return result, nil
+}
+
+// Format formats the file in canonical form using the format implied by
+// the file name. Services, servers, resources, var sets, and flows are
+// sorted by name.
+func Format(file *File, filename string) ([]byte, error) {
+	sortFile(file)
+	return marshalFile(file, filename)
+}
+
+func marshalFile(file *File, filename string) ([]byte, error) {
+	var v any = file
+	switch filepath.Ext(filename) {
+	case ".yamlvars", ".ymlvars":
+		v = file.VarSets
+	}
+
+	switch filepath.Ext(filename) {
+	case ".json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+
+	case ".yaml", ".yml", ".yamlvars", ".ymlvars":
+		return yaml.Marshal(v)
+	}
+	return nil, fmt.Errorf("format file %s: %w", filename, ErrUnsupportedFileFormat)
+}
+
+// FormatSource formats the source of the file in canonical form like Format.
+// YAML files are formatted in place so that comments, anchors, aliases,
+// and templates are preserved; the order of keys is also kept.
+func FormatSource(data []byte, filename string) ([]byte, error) {
+	ext := filepath.Ext(filename)
+	switch ext {
+	case ".yaml", ".yml", ".yamlvars", ".ymlvars":
+		return formatYAML(data, ext == ".yamlvars" || ext == ".ymlvars")
+
+	case ".json":
+		file := new(File)
+		if err := json.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		return Format(file, filename)
+	}
+	return nil, fmt.Errorf("format file %s: %w", filename, ErrUnsupportedFileFormat)
+}
+
+// writable determines whether the format of the file can be written
+func writable(filename string) bool {
+	switch filepath.Ext(filename) {
+	case ".json", ".yaml", ".yml", ".yamlvars", ".ymlvars":
+		return true
+	}
+	return false
+}
+
+func sortFile(f *File) {
+	if f.Service != nil {
+		sortService(f.Service)
+	}
+	for i := range f.Services {
+		sortService(&f.Services[i])
+	}
+	sortByName(f.Services, func(s Service) string { return s.Name })
+	sortByName(f.VarSets, func(s VarSet) string { return s.Name })
+	sortByName(f.Flows, func(s Flow) string { return s.Name })
+}
+
+func sortService(s *Service) {
+	sortByName(s.Servers, func(s Server) string { return s.Name })
+	sortByName(s.VarSets, func(s VarSet) string { return s.Name })
+	sortResources(s.Resources)
+}
+
+func sortResources(resources []Resource) {
+	for i := range resources {
+		sortByName(resources[i].VarSets, func(s VarSet) string { return s.Name })
+		sortResources(resources[i].Resources)
+	}
+	sortByName(resources, func(r Resource) string { return r.Name })
+}
+
+func sortByName[T any](values []T, name func(T) string) {
+	slices.SortStableFunc(values, func(x, y T) int {
+		return cmp.Compare(name(x), name(y))
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config_test
+
+import (
+	"os"
+	"testing/fstest"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+
+	It("sorts by name", func() {
+		file, err := config.ReadFile(fstest.MapFS{
+			"a.yml": {Data: []byte(`
+services:
+- name: b
+  resources:
+  - uri: /z
+    name: z
+  - name: a
+    uri: /a
+- name: a
+`)},
+		}, "a.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := config.Format(file, "a.yml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`services:
+- name: a
+- name: b
+  resources:
+  - name: a
+    uri: /a
+  - name: z
+    uri: /z
+`))
+	})
+
+	It("is idempotent", func() {
+		file, _ := config.ReadFile(os.DirFS("testdata/valid-examples"), "flows.yml")
+		data, err := config.Format(file, "flows.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		file, _ = config.ReadFile(fstest.MapFS{"flows.yml": {Data: data}}, "flows.yml")
+		Expect(config.Format(file, "flows.yml")).To(Equal(data))
+	})
+
+	It("formats JSON", func() {
+		file, _ := config.ReadFile(fstest.MapFS{
+			"a.json": {Data: []byte(`{"name":"a","servers":[{"name":"b","baseUrl":"/"}]}`)},
+		}, "a.json")
+
+		Expect(config.Format(file, "a.json")).To(BeEquivalentTo(`{
+  "name": "a",
+  "servers": [
+    {
+      "name": "b",
+      "baseUrl": "/"
+    }
+  ]
+}
+`))
+	})
+
+	It("formats var sets", func() {
+		file, _ := config.ReadFile(os.DirFS("testdata/valid-examples"), "vars.ymlvars")
+		data, err := config.Format(file, "vars.ymlvars")
+		Expect(err).NotTo(HaveOccurred())
+
+		file, err = config.ReadFile(fstest.MapFS{"vars.ymlvars": {Data: data}}, "vars.ymlvars")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.VarSets).NotTo(BeEmpty())
+	})
+
+	Describe("FormatSource", func() {
+
+		It("sorts by name and preserves comments", func() {
+			data, err := config.FormatSource([]byte(`
+# Services
+services:
+  # The b service
+  - name: b
+    resources:
+      - uri: /z
+        name: z
+      - name: a
+        uri: /a
+  - name: a # The a service
+`), "a.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`# Services
+services:
+- name: a # The a service
+# The b service
+- name: b
+  resources:
+  - name: a
+    uri: /a
+  - uri: /z
+    name: z
+`))
+		})
+
+		It("preserves anchors and templates", func() {
+			data, _ := os.ReadFile("testdata/valid-examples/preprocessed.yml")
+			actual, err := config.FormatSource(data, "preprocessed.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual)).To(Equal(`.server: &server
+  name: s
+  title: t
+name: p
+servers:
+- <<: *server
+`))
+		})
+
+		It("sorts by name using merge keys", func() {
+			data, err := config.FormatSource([]byte(`.b: &b
+  name: b
+name: p
+servers:
+- <<: *b
+- name: a
+`), "a.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HaveSuffix(`servers:
+- name: a
+- <<: *b
+`))
+		})
+
+		It("does not sort sequences which define anchors", func() {
+			source := `name: p
+servers:
+- &b
+  name: b
+- <<: *b
+  name: a
+`
+			data, err := config.FormatSource([]byte(source), "a.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(source))
+		})
+
+		It("is idempotent", func() {
+			data, _ := os.ReadFile("testdata/valid-examples/flows.yml")
+			data, err := config.FormatSource(data, "flows.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.FormatSource(data, "flows.yml")).To(Equal(data))
+		})
+
+		It("sorts var sets", func() {
+			data, err := config.FormatSource([]byte(`- name: b
+- name: a
+`), "vars.ymlvars")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(`- name: a
+- name: b
+`))
+		})
+
+		It("returns an error for unsupported files", func() {
+			_, err := config.FormatSource(nil, "requests.http")
+			Expect(err).To(MatchError(config.ErrUnsupportedFileFormat))
+		})
+	})
+
+	It("does not resolve sourced files", func() {
+		file, err := config.ReadFile(os.DirFS("testdata/valid-examples"), "include.yml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Service.Resources).To(ConsistOf(
+			config.Resource{Source: "_basic.resource.yml"},
+			config.Resource{Source: "./group/_a.resource.json"},
+		))
+	})
+})
@@ -0,0 +1,239 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	httpFileVar     = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+	httpFileAnyVar  = regexp.MustCompile(`\{\{.*?\}\}`)
+	httpFileVarDecl = regexp.MustCompile(`^@([A-Za-z_][A-Za-z0-9_-]*)\s*=\s*(.*)$`)
+	httpFileName    = regexp.MustCompile(`^(?:#|//)\s*@name\s+(\S+)`)
+	httpFileMethod  = regexp.MustCompile(`^[A-Z]+$`)
+)
+
+// httpRequest is a request which was read from an .http file
+type httpRequest struct {
+	name    string
+	title   string
+	method  string
+	url     string
+	headers Header
+	body    []string
+}
+
+// unmarshalHTTPFile reads the requests of an .http or .rest file as used
+// by the request runners of editors. The file provides a service named
+// after the file. Requests are separated by ### and become endpoints, and
+// file variables declared using @name = value become vars. Variables which
+// are not file variables, such as {{$guid}}, are left as they are except in
+// the URL, where they are an error.
+func unmarshalHTTPFile(data []byte, v any) error {
+	file := v.(*File)
+	name := strings.TrimSuffix(filepath.Base(file.Name()), filepath.Ext(file.Name()))
+	svc := &Service{
+		Name: cmp.Or(Identifier(name), "http"),
+	}
+
+	requests, vars, err := parseHTTPFile(data)
+	if err != nil {
+		return err
+	}
+	if len(vars) > 0 {
+		svc.Vars = vars
+	}
+
+	for i, r := range requests {
+		ep, err := r.endpoint()
+		if err != nil {
+			return fmt.Errorf("request %d: %w", i+1, err)
+		}
+
+		uri := httpFileURI(r.url)
+		if v := httpFileAnyVar.FindString(uri); v != "" {
+			return fmt.Errorf("request %d: variable %s is not supported in the URL", i+1, v)
+		}
+		j := slices.IndexFunc(svc.Resources, func(t Resource) bool {
+			_, exists := t.Endpoint(r.method)
+			return t.URI == uri && !exists
+		})
+		if j < 0 {
+			svc.Resources = append(svc.Resources, Resource{
+				Name: cmp.Or(r.name, Identifier(r.title), fmt.Sprintf("request%d", i+1)),
+				URI:  uri,
+			})
+			j = len(svc.Resources) - 1
+		}
+		svc.Resources[j].SetEndpoint(r.method, ep)
+	}
+
+	file.Service = svc
+	return nil
+}
+
+func parseHTTPFile(data []byte) ([]*httpRequest, map[string]any, error) {
+	var (
+		requests []*httpRequest
+		current  = &httpRequest{}
+		vars     = map[string]any{}
+		inBody   bool
+	)
+	flush := func() {
+		if current.url != "" {
+			requests = append(requests, current)
+		}
+		current = &httpRequest{}
+		inBody = false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if title, ok := strings.CutPrefix(trimmed, "###"); ok {
+			flush()
+			current.title = strings.TrimSpace(title)
+			continue
+		}
+
+		switch {
+		case inBody:
+			current.body = append(current.body, line)
+
+		case current.url == "":
+			// Before the request line, there can be comments, names, and
+			// file variables
+			if m := httpFileName.FindStringSubmatch(trimmed); m != nil {
+				current.name = m[1]
+			} else if m := httpFileVarDecl.FindStringSubmatch(trimmed); m != nil {
+				vars[m[1]] = httpFileExpr(strings.TrimSpace(m[2]))
+			} else if trimmed != "" && !isHTTPFileComment(trimmed) {
+				current.method, current.url = parseHTTPFileRequestLine(trimmed)
+			}
+
+		case trimmed == "":
+			inBody = true
+
+		case strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&"):
+			// Query parameters can continue on subsequent lines
+			current.url += trimmed
+
+		case isHTTPFileComment(trimmed):
+
+		default:
+			k, v, ok := strings.Cut(trimmed, ":")
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid header %q", trimmed)
+			}
+			if current.headers == nil {
+				current.headers = Header{}
+			}
+			k = strings.TrimSpace(k)
+			current.headers[k] = append(current.headers[k], httpFileExpr(strings.TrimSpace(v)))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	flush()
+	return requests, vars, nil
+}
+
+func parseHTTPFileRequestLine(line string) (string, string) {
+	fields := strings.Fields(line)
+	method := "GET"
+	if len(fields) > 1 && httpFileMethod.MatchString(fields[0]) {
+		method, fields = fields[0], fields[1:]
+	}
+	if len(fields) > 1 && strings.HasPrefix(fields[len(fields)-1], "HTTP/") {
+		fields = fields[:len(fields)-1]
+	}
+	return method, strings.Join(fields, " ")
+}
+
+func (r *httpRequest) endpoint() (*Endpoint, error) {
+	ep := &Endpoint{
+		Name: r.name,
+		Metadata: Metadata{
+			Title: r.title,
+		},
+		Headers: r.headers,
+	}
+
+	if _, rawQuery, ok := strings.Cut(r.url, "?"); ok {
+		query, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, err
+		}
+		for k, values := range query {
+			if ep.Query == nil {
+				ep.Query = Header{}
+			}
+			for _, v := range values {
+				ep.Query[k] = append(ep.Query[k], httpFileExpr(v))
+			}
+		}
+	}
+
+	body := httpFileExpr(strings.TrimSpace(strings.Join(r.body, "\n")))
+	if body == "" {
+		return ep, nil
+	}
+
+	var contentType string
+	for k, v := range r.headers {
+		if strings.EqualFold(k, "Content-Type") && len(v) > 0 {
+			contentType = v[0]
+		}
+	}
+
+	var decoded any
+	switch {
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(strings.ReplaceAll(body, "\n", ""))
+		if err != nil {
+			return nil, err
+		}
+		ep.Form = Form(form)
+	case json.Unmarshal([]byte(body), &decoded) == nil:
+		ep.Body = decoded
+	default:
+		ep.RawBody = body
+	}
+	return ep, nil
+}
+
+// httpFileURI converts the URL of the request into a URI template. The
+// query is omitted because it is provided by the endpoint. A variable
+// which provides the start of the URL is expanded without escaping its
+// reserved characters because it typically contains the base URL.
+func httpFileURI(u string) string {
+	u, _, _ = strings.Cut(u, "?")
+	if loc := httpFileVar.FindStringSubmatchIndex(u); loc != nil && loc[0] == 0 {
+		u = "{+" + u[loc[2]:loc[3]] + "}" + u[loc[1]:]
+	}
+	return httpFileVar.ReplaceAllString(u, "{$1}")
+}
+
+// httpFileExpr converts the variables in s to Pastiche expressions
+func httpFileExpr(s string) string {
+	return httpFileVar.ReplaceAllString(s, "$${$1}")
+}
+
+func isHTTPFileComment(s string) bool {
+	return strings.HasPrefix(s, "#") || strings.HasPrefix(s, "//")
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config_test
+
+import (
+	"os"
+	"testing/fstest"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTTP files", func() {
+
+	var subject = func() *config.Service {
+		file, err := config.LoadFile(os.DirFS("testdata"), "valid-examples/requests.http")
+		Expect(err).NotTo(HaveOccurred())
+		return file.Service
+	}
+
+	It("names the service after the file", func() {
+		Expect(subject().Name).To(Equal("requests"))
+	})
+
+	It("converts file variables to vars", func() {
+		Expect(subject().Vars).To(Equal(map[string]any{
+			"baseUrl": "https://api.example.com",
+			"token":   "abc",
+		}))
+	})
+
+	It("converts requests with the same URL to endpoints of one resource", func() {
+		r := subject().Resources[0]
+		Expect(r.Name).To(Equal("listUsers"))
+		Expect(r.URI).To(Equal("{+baseUrl}/users"))
+		Expect(r.Get).To(Equal(&config.Endpoint{
+			Name:     "listUsers",
+			Metadata: config.Metadata{Title: "List users"},
+			Headers: config.Header{
+				"Accept":        {"application/json"},
+				"Authorization": {"Bearer ${token}"},
+			},
+			Query: config.Header{"limit": {"10"}},
+		}))
+		Expect(r.Post).To(Equal(&config.Endpoint{
+			Metadata: config.Metadata{Title: "Create user"},
+			Headers:  config.Header{"Content-Type": {"application/json"}},
+			Body:     map[string]any{"name": "${name}"},
+		}))
+	})
+
+	It("names resources by their position when they have no name", func() {
+		r := subject().Resources[1]
+		Expect(r.Name).To(Equal("request3"))
+		Expect(r.URI).To(Equal("https://example.com/health"))
+		Expect(r.Get.Query).To(Equal(config.Header{"verbose": {"1"}}))
+	})
+
+	It("converts URL-encoded bodies to the form", func() {
+		r := subject().Resources[2]
+		Expect(r.Name).To(Equal("Login"))
+		Expect(r.Post.Form).To(Equal(config.Form{
+			"user":     {"a"},
+			"password": {"${password}"},
+		}))
+	})
+
+	It("returns an error for invalid headers", func() {
+		_, err := config.LoadFile(fstest.MapFS{
+			"bad.rest": {Data: []byte("GET https://example.com\nnot a header\n")},
+		}, "bad.rest")
+		Expect(err).To(MatchError(`invalid header "not a header"`))
+	})
+
+	It("returns an error for dynamic variables in the URL", func() {
+		_, err := config.LoadFile(fstest.MapFS{
+			"dynamic.http": {Data: []byte("GET https://example.com/{{$guid}}\nX-Request-Id: {{$guid}}\n")},
+		}, "dynamic.http")
+		Expect(err).To(MatchError("request 1: variable {{$guid}} is not supported in the URL"))
+	})
+
+	It("leaves dynamic variables in headers", func() {
+		file, err := config.LoadFile(fstest.MapFS{
+			"dynamic.http": {Data: []byte("GET https://example.com/\nX-Request-Id: {{$guid}}\n")},
+		}, "dynamic.http")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Service.Resources[0].Get.Headers).To(Equal(config.Header{"X-Request-Id": {"{{$guid}}"}}))
+	})
+})
+
+var _ = DescribeTable("Identifier", func(s, expected string) {
+	Expect(config.Identifier(s)).To(Equal(expected))
+},
+	Entry("valid", "users", "users"),
+	Entry("replaces runs of characters", "List all users!", "List-all-users"),
+	Entry("starts with digit", "2fa", "_2fa"),
+)
@@ -0,0 +1,264 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrEndpointExists is returned when merging would replace an endpoint
+// which is already defined
+var ErrEndpointExists = errors.New("endpoint already exists")
+
+// MergeService merges the servers, resources, vars, and var sets of src
+// into dst. Servers, var sets, and resources are matched by name, or by URI
+// when a resource has no name. Anything which only exists in src is added;
+// existing servers, resources, endpoints, and vars are left intact. An error
+// is returned if src defines an endpoint for a method which dst already
+// defines.
+func MergeService(dst, src *Service) error {
+	for _, s := range src.Servers {
+		if !slices.ContainsFunc(dst.Servers, func(t Server) bool { return t.Name == s.Name }) {
+			dst.Servers = append(dst.Servers, s)
+		}
+	}
+	dst.Vars = mergeVars(dst.Vars, src.Vars)
+	dst.VarSets = mergeVarSets(dst.VarSets, src.VarSets)
+
+	var err error
+	dst.Resources, err = mergeResources(dst.Resources, src.Resources, []string{dst.Name})
+	return err
+}
+
+func mergeResources(dst, src []Resource, path []string) ([]Resource, error) {
+	for _, r := range src {
+		i := slices.IndexFunc(dst, func(t Resource) bool { return sameResource(t, r) })
+		if i < 0 {
+			dst = append(dst, r)
+			continue
+		}
+		if err := mergeResource(&dst[i], &r, append(path, resourceName(r))); err != nil {
+			return nil, err
+		}
+	}
+	return dst, nil
+}
+
+func mergeResource(dst, src *Resource, path []string) error {
+	for method, ep := range src.Endpoints() {
+		if _, ok := dst.Endpoint(method); ok {
+			return fmt.Errorf("%w: %s %s", ErrEndpointExists, method, strings.Join(path, "."))
+		}
+		dst.SetEndpoint(method, ep)
+	}
+	if dst.URI == "" {
+		dst.URI = src.URI
+	}
+
+	var err error
+	dst.Resources, err = mergeResources(dst.Resources, src.Resources, path)
+	return err
+}
+
+func sameResource(x, y Resource) bool {
+	if x.Name != "" || y.Name != "" {
+		return x.Name == y.Name
+	}
+	return x.URI == y.URI
+}
+
+func resourceName(r Resource) string {
+	if r.Name == "" {
+		return r.URI
+	}
+	return r.Name
+}
+
+func mergeVars[V any](dst, src map[string]V) map[string]V {
+	for k, v := range src {
+		if dst == nil {
+			dst = map[string]V{}
+		}
+		if _, ok := dst[k]; !ok {
+			dst[k] = v
+		}
+	}
+	return dst
+}
+
+func mergeVarSets(dst, src []VarSet) []VarSet {
+	for _, v := range src {
+		i := slices.IndexFunc(dst, func(t VarSet) bool { return t.Name == v.Name })
+		if i < 0 {
+			dst = append(dst, v)
+			continue
+		}
+		dst[i].Vars = mergeVars(dst[i].Vars, v.Vars)
+	}
+	return dst
+}
+
+// MergeServiceSource merges the service into the service with the same name
+// defined by the source of the file. Servers, resources, and endpoints are
+// added after the existing ones, which are left in their original order. YAML
+// files are edited in place so that comments, anchors, aliases, and templates
+// are preserved.
+func MergeServiceSource(data []byte, filename string, svc *Service) ([]byte, error) {
+	unmarshal, ok := unmarshalers[filepath.Ext(filename)]
+	if !ok || !writable(filename) {
+		return nil, fmt.Errorf("merge file %s: %w", filename, ErrUnsupportedFileFormat)
+	}
+
+	file := new(File)
+	file.SetName(filename)
+	if err := unmarshal(data, file); err != nil {
+		return nil, err
+	}
+	existing := file.FindService(svc.Name)
+	if existing == nil {
+		return nil, fmt.Errorf("service %q not found", svc.Name)
+	}
+	if err := MergeService(existing, svc); err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml":
+	default:
+		return marshalFile(file, filename)
+	}
+
+	doc, err := parseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	src, err := serviceNode(svc)
+	if err != nil {
+		return nil, err
+	}
+	mergeServiceNode(findServiceNode(doc.Content[0], svc.Name), src)
+	return encodeYAML(doc)
+}
+
+func serviceNode(svc *Service) (*yamlv3.Node, error) {
+	data, err := yaml.Marshal(svc)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Content[0], nil
+}
+
+func findServiceNode(root *yamlv3.Node, name string) *yamlv3.Node {
+	if scalarValue(root, "name") == name {
+		return root
+	}
+	seq := ownValue(root, "services")
+	i := slices.IndexFunc(seq.Content, func(n *yamlv3.Node) bool {
+		return scalarValue(n, "name") == name
+	})
+	return ownMapping(seq.Content[i])
+}
+
+// mergeServiceNode merges the service node like MergeService. Conflicts
+// were already detected when merging the services, so src only adds to dst.
+func mergeServiceNode(dst, src *yamlv3.Node) {
+	for _, s := range items(keyValue(src, "servers")) {
+		if indexNode(lookupValue(dst, "servers"), sameNameNode(s)) < 0 {
+			appendItem(dst, "servers", s)
+		}
+	}
+	mergeKeys(dst, src, "vars")
+
+	for _, v := range items(keyValue(src, "varSets")) {
+		i := indexNode(lookupValue(dst, "varSets"), sameNameNode(v))
+		if i < 0 {
+			appendItem(dst, "varSets", v)
+			continue
+		}
+		mergeKeys(ownMapping(ownValue(dst, "varSets").Content[i]), v, "vars")
+	}
+	mergeResourceNodes(dst, src)
+}
+
+func mergeResourceNodes(dst, src *yamlv3.Node) {
+	for _, r := range items(keyValue(src, "resources")) {
+		i := indexNode(lookupValue(dst, "resources"), func(t *yamlv3.Node) bool {
+			return sameResourceNode(t, r)
+		})
+		if i < 0 {
+			appendItem(dst, "resources", r)
+			continue
+		}
+		mergeResourceNode(ownMapping(ownValue(dst, "resources").Content[i]), r)
+	}
+}
+
+func mergeResourceNode(dst, src *yamlv3.Node) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i].Value, src.Content[i+1]
+		switch key {
+		case "get", "put", "post", "delete", "options", "head", "trace", "patch":
+			if lookupValue(dst, key) == nil {
+				appendKey(dst, key, value)
+			}
+		case "methods":
+			mergeKeys(dst, src, key)
+		case "uri":
+			if scalarValue(dst, key) != "" {
+				continue
+			}
+			if uri := keyValue(dst, key); uri != nil {
+				uri.Value = value.Value
+				continue
+			}
+			appendKey(dst, key, value)
+		}
+	}
+	mergeResourceNodes(dst, src)
+}
+
+func sameNameNode(x *yamlv3.Node) func(*yamlv3.Node) bool {
+	return func(y *yamlv3.Node) bool {
+		return scalarValue(x, "name") == scalarValue(y, "name")
+	}
+}
+
+func sameResourceNode(x, y *yamlv3.Node) bool {
+	xname, yname := scalarValue(x, "name"), scalarValue(y, "name")
+	if xname != "" || yname != "" {
+		return xname == yname
+	}
+	return scalarValue(x, "uri") == scalarValue(y, "uri")
+}
+
+// mergeKeys adds the keys of the mapping under key in src which are missing
+// from the mapping under key in dst
+func mergeKeys(dst, src *yamlv3.Node, key string) {
+	s := keyValue(src, key)
+	if s == nil {
+		return
+	}
+	d := ownValue(dst, key)
+	if d == nil {
+		appendKey(dst, key, s)
+		return
+	}
+	for i := 0; i+1 < len(s.Content); i += 2 {
+		if lookupValue(d, s.Content[i].Value) == nil {
+			d.Content = append(d.Content, s.Content[i], s.Content[i+1])
+		}
+	}
+}
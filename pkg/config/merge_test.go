@@ -0,0 +1,284 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config_test
+
+import (
+	"github.com/Carbonfrost/pastiche/pkg/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MergeService", func() {
+
+	var existing = func() *config.Service {
+		return &config.Service{
+			Name: "s",
+			Metadata: config.Metadata{
+				Comment: "kept",
+			},
+			Servers: []config.Server{
+				{Name: "default", BaseURL: "https://example.com"},
+			},
+			Resources: []config.Resource{
+				{
+					Name: "users",
+					URI:  "/users",
+					Get:  &config.Endpoint{Name: "list"},
+					Resources: []config.Resource{
+						{Source: "_user.resource.yml"},
+					},
+				},
+			},
+		}
+	}
+
+	DescribeTable("examples", func(src config.Service, expected *config.Service) {
+		dst := existing()
+		err := config.MergeService(dst, &src)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst).To(Equal(expected))
+	},
+		Entry("new server",
+			config.Service{
+				Name: "s",
+				Servers: []config.Server{
+					{Name: "default", BaseURL: "https://other.example"},
+					{Name: "staging"},
+				},
+			},
+			func() *config.Service {
+				s := existing()
+				s.Servers = append(s.Servers, config.Server{Name: "staging"})
+				return s
+			}(),
+		),
+		Entry("new resource",
+			config.Service{
+				Name: "s",
+				Resources: []config.Resource{
+					{Name: "groups", Get: &config.Endpoint{}},
+				},
+			},
+			func() *config.Service {
+				s := existing()
+				s.Resources = append(s.Resources, config.Resource{Name: "groups", Get: &config.Endpoint{}})
+				return s
+			}(),
+		),
+		Entry("new endpoint",
+			config.Service{
+				Name: "s",
+				Resources: []config.Resource{
+					{Name: "users", Post: &config.Endpoint{Name: "create"}},
+				},
+			},
+			func() *config.Service {
+				s := existing()
+				s.Resources[0].Post = &config.Endpoint{Name: "create"}
+				return s
+			}(),
+		),
+		Entry("custom method",
+			config.Service{
+				Name: "s",
+				Resources: []config.Resource{
+					{Name: "users", Methods: config.Methods{"PROPFIND": &config.Endpoint{}}},
+				},
+			},
+			func() *config.Service {
+				s := existing()
+				s.Resources[0].Methods = config.Methods{"PROPFIND": &config.Endpoint{}}
+				return s
+			}(),
+		),
+		Entry("vars and var sets",
+			config.Service{
+				Name: "s",
+				Vars: map[string]any{"a": "1"},
+				VarSets: []config.VarSet{
+					{Name: "env", Vars: map[string]map[string]any{"staging": {"b": "2"}}},
+				},
+			},
+			func() *config.Service {
+				s := existing()
+				s.Vars = map[string]any{"a": "1"}
+				s.VarSets = []config.VarSet{
+					{Name: "env", Vars: map[string]map[string]any{"staging": {"b": "2"}}},
+				}
+				return s
+			}(),
+		),
+		Entry("nested resource",
+			config.Service{
+				Name: "s",
+				Resources: []config.Resource{
+					{
+						Name: "users",
+						Resources: []config.Resource{
+							{Name: "user", URI: "{id}", Delete: &config.Endpoint{}},
+						},
+					},
+				},
+			},
+			func() *config.Service {
+				s := existing()
+				s.Resources[0].Resources = append(s.Resources[0].Resources,
+					config.Resource{Name: "user", URI: "{id}", Delete: &config.Endpoint{}},
+				)
+				return s
+			}(),
+		),
+	)
+
+	It("returns an error when the endpoint exists", func() {
+		err := config.MergeService(existing(), &config.Service{
+			Name: "s",
+			Resources: []config.Resource{
+				{Name: "users", Get: &config.Endpoint{}},
+			},
+		})
+		Expect(err).To(MatchError(config.ErrEndpointExists))
+		Expect(err).To(MatchError("endpoint already exists: GET s.users"))
+	})
+})
+
+var _ = Describe("MergeServiceSource", func() {
+
+	It("adds to the service and preserves comments and anchors", func() {
+		data, err := config.MergeServiceSource([]byte(`# The service
+.server: &server
+  baseUrl: https://example.com
+name: s
+servers:
+- <<: *server
+  name: default
+resources:
+- name: users # Users
+  uri: /users
+  get:
+    name: list
+`), "s.yml", &config.Service{
+			Name: "s",
+			Servers: []config.Server{
+				{Name: "default", BaseURL: "https://other.example"},
+				{Name: "staging", BaseURL: "https://staging.example"},
+			},
+			Vars: map[string]any{"v": "1"},
+			Resources: []config.Resource{
+				{Name: "users", URI: "/people", Post: &config.Endpoint{Name: "create"}},
+				{Name: "groups", URI: "/groups"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`# The service
+.server: &server
+  baseUrl: https://example.com
+name: s
+servers:
+- <<: *server
+  name: default
+- baseUrl: https://staging.example
+  name: staging
+resources:
+- name: users # Users
+  uri: /users
+  get:
+    name: list
+  post:
+    name: create
+- name: groups
+  uri: /groups
+vars:
+  v: "1"
+`))
+	})
+
+	It("leaves existing servers and resources in their order", func() {
+		source := `name: s
+servers:
+- name: staging
+  baseUrl: https://staging.example
+- name: default
+  baseUrl: https://example.com
+resources:
+- uri: /users
+  name: users
+  get:
+    name: list
+- name: groups
+  uri: /groups
+`
+		data, err := config.MergeServiceSource([]byte(source), "s.yml", &config.Service{
+			Name: "s",
+			Resources: []config.Resource{
+				{Name: "accounts", URI: "/accounts"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(source + `- name: accounts
+  uri: /accounts
+`))
+	})
+
+	It("adds keys to aliased resources using merge keys", func() {
+		data, err := config.MergeServiceSource([]byte(`.users: &users
+  name: users
+services:
+- name: s
+  resources:
+  - *users
+`), "s.yml", &config.Service{
+			Name: "s",
+			Resources: []config.Resource{
+				{Name: "users", URI: "/users"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(HaveSuffix(`  resources:
+  - <<: *users
+    uri: /users
+`))
+	})
+
+	It("returns an error when the endpoint exists", func() {
+		_, err := config.MergeServiceSource([]byte(`name: s
+resources:
+- name: users
+  get: {}
+`), "s.yml", &config.Service{
+			Name: "s",
+			Resources: []config.Resource{
+				{Name: "users", Get: &config.Endpoint{}},
+			},
+		})
+		Expect(err).To(MatchError(config.ErrEndpointExists))
+	})
+
+	It("formats JSON files", func() {
+		data, err := config.MergeServiceSource(
+			[]byte(`{"name":"s"}`),
+			"s.json",
+			&config.Service{Name: "s", Vars: map[string]any{"v": "1"}},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(`{
+  "name": "s",
+  "vars": {
+    "v": "1"
+  }
+}
+`))
+	})
+
+	It("returns an error for files which cannot be written", func() {
+		_, err := config.MergeServiceSource(
+			[]byte("GET https://example.com/users\n"),
+			"s.http",
+			&config.Service{Name: "s"},
+		)
+		Expect(err).To(MatchError(config.ErrUnsupportedFileFormat))
+	})
+})
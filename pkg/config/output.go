@@ -0,0 +1,28 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"encoding/json"
+)
+
+// UnmarshalJSON allows the query to be specified as a string as shorthand
+func (j *JMESPathOutput) UnmarshalJSON(data []byte) error {
+	type plain JMESPathOutput
+	return unmarshalQuery(data, &j.Query, (*plain)(j))
+}
+
+// UnmarshalJSON allows the query to be specified as a string as shorthand
+func (d *DigOutput) UnmarshalJSON(data []byte) error {
+	type plain DigOutput
+	return unmarshalQuery(data, &d.Query, (*plain)(d))
+}
+
+func unmarshalQuery(data []byte, query *string, v any) error {
+	if err := json.Unmarshal(data, query); err == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
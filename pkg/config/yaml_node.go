@@ -0,0 +1,264 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+// formatYAML formats YAML source in canonical form. Because the document is
+// edited as nodes, comments, anchors, aliases, and templates are preserved.
+func formatYAML(data []byte, varSets bool) ([]byte, error) {
+	doc, err := parseYAML(data)
+	if err != nil || doc == nil {
+		return data, err
+	}
+
+	root := doc.Content[0]
+	if varSets {
+		sortNamedNodes(root)
+	} else {
+		sortFileNode(root)
+	}
+	return encodeYAML(doc)
+}
+
+// parseYAML parses the document node, which is nil when the document
+// is empty
+func parseYAML(data []byte) (*yamlv3.Node, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return &doc, nil
+}
+
+func encodeYAML(doc *yamlv3.Node) ([]byte, error) {
+	clearMergeTags(doc)
+
+	var buf bytes.Buffer
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(2)
+	enc.CompactSeqIndent()
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clearMergeTags clears the tag of merge keys, which the encoder would
+// otherwise write explicitly as "!!merge <<"
+func clearMergeTags(n *yamlv3.Node) {
+	if isMergeKey(n) {
+		n.Tag = ""
+	}
+	for _, c := range n.Content {
+		clearMergeTags(c)
+	}
+}
+
+func sortFileNode(n *yamlv3.Node) {
+	sortServiceNode(n)
+	for _, s := range itemNodes(n, "services") {
+		sortServiceNode(s)
+	}
+	sortNamedNodes(keyValue(n, "services"))
+	sortNamedNodes(keyValue(n, "varSets"))
+	sortNamedNodes(keyValue(n, "flows"))
+}
+
+func sortServiceNode(n *yamlv3.Node) {
+	sortNamedNodes(keyValue(n, "servers"))
+	sortNamedNodes(keyValue(n, "varSets"))
+	sortResourceNodes(n)
+}
+
+func sortResourceNodes(n *yamlv3.Node) {
+	for _, r := range itemNodes(n, "resources") {
+		sortNamedNodes(keyValue(r, "varSets"))
+		sortResourceNodes(r)
+	}
+	sortNamedNodes(keyValue(n, "resources"))
+}
+
+// sortNamedNodes sorts the items of the sequence by name. Sequences which
+// define anchors are left as they are because an alias must follow its
+// anchor.
+func sortNamedNodes(seq *yamlv3.Node) {
+	if seq == nil || seq.Kind != yamlv3.SequenceNode || hasAnchor(seq) {
+		return
+	}
+	slices.SortStableFunc(seq.Content, func(x, y *yamlv3.Node) int {
+		return cmp.Compare(scalarValue(x, "name"), scalarValue(y, "name"))
+	})
+}
+
+func hasAnchor(n *yamlv3.Node) bool {
+	if n.Anchor != "" {
+		return true
+	}
+	return slices.ContainsFunc(n.Content, hasAnchor)
+}
+
+// itemNodes gets the items of the sequence under the key which are
+// defined in place, excluding aliases
+func itemNodes(n *yamlv3.Node, key string) []*yamlv3.Node {
+	seq := keyValue(n, key)
+	if seq == nil || seq.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+	var res []*yamlv3.Node
+	for _, item := range seq.Content {
+		if item.Kind == yamlv3.MappingNode {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// keyValue gets the value of the key which is defined in place in the
+// mapping, which is nil if the key is missing
+func keyValue(n *yamlv3.Node, key string) *yamlv3.Node {
+	if n == nil || n.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key && !isMergeKey(n.Content[i]) {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// lookupValue gets the value of the key in the mapping, following aliases
+// and merge keys
+func lookupValue(n *yamlv3.Node, key string) *yamlv3.Node {
+	n = resolveAlias(n)
+	if v := keyValue(n, key); v != nil {
+		return v
+	}
+	if n == nil || n.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if !isMergeKey(n.Content[i]) {
+			continue
+		}
+		merged := resolveAlias(n.Content[i+1])
+		sources := []*yamlv3.Node{merged}
+		if merged.Kind == yamlv3.SequenceNode {
+			sources = merged.Content
+		}
+		for _, s := range sources {
+			if v := lookupValue(s, key); v != nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+func scalarValue(n *yamlv3.Node, key string) string {
+	if v := resolveAlias(lookupValue(n, key)); v != nil && v.Kind == yamlv3.ScalarNode {
+		return v.Value
+	}
+	return ""
+}
+
+func items(seq *yamlv3.Node) []*yamlv3.Node {
+	if seq = resolveAlias(seq); seq == nil || seq.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+	return seq.Content
+}
+
+func indexNode(seq *yamlv3.Node, f func(*yamlv3.Node) bool) int {
+	return slices.IndexFunc(items(seq), f)
+}
+
+// ownValue gets the value of the key in the mapping so that it can be
+// modified without affecting the nodes which it aliases. Values which are
+// aliased or provided by merge keys are copied into the mapping.
+func ownValue(n *yamlv3.Node, key string) *yamlv3.Node {
+	if v := keyValue(n, key); v != nil {
+		if v.Kind == yamlv3.AliasNode {
+			*v = *copyNode(resolveAlias(v))
+		}
+		return v
+	}
+	if v := lookupValue(n, key); v != nil {
+		v = copyNode(resolveAlias(v))
+		appendKey(n, key, v)
+		return v
+	}
+	return nil
+}
+
+// ownMapping converts an alias of a mapping into a mapping which merges the
+// alias so that keys can be added to it
+func ownMapping(n *yamlv3.Node) *yamlv3.Node {
+	if n.Kind == yamlv3.AliasNode {
+		alias := *n
+		*n = yamlv3.Node{
+			Kind: yamlv3.MappingNode,
+			Tag:  "!!map",
+			Content: []*yamlv3.Node{
+				{Kind: yamlv3.ScalarNode, Tag: "!!merge", Value: "<<"},
+				&alias,
+			},
+		}
+	}
+	return n
+}
+
+func copyNode(n *yamlv3.Node) *yamlv3.Node {
+	res := *n
+	res.Anchor = ""
+	res.Content = make([]*yamlv3.Node, len(n.Content))
+	for i, c := range n.Content {
+		res.Content[i] = copyNode(c)
+	}
+	return &res
+}
+
+func appendKey(n *yamlv3.Node, key string, value *yamlv3.Node) {
+	n.Content = append(n.Content, &yamlv3.Node{
+		Kind:  yamlv3.ScalarNode,
+		Tag:   "!!str",
+		Value: key,
+	}, value)
+}
+
+// appendItem appends to the sequence under the key, which is added when
+// it is missing
+func appendItem(n *yamlv3.Node, key string, item *yamlv3.Node) {
+	seq := ownValue(n, key)
+	if seq == nil {
+		seq = &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		appendKey(n, key, seq)
+	}
+	seq.Content = append(seq.Content, item)
+}
+
+func resolveAlias(n *yamlv3.Node) *yamlv3.Node {
+	for n != nil && n.Kind == yamlv3.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+func isMergeKey(n *yamlv3.Node) bool {
+	return n.Kind == yamlv3.ScalarNode && n.Value == "<<" && n.ShortTag() == "!!merge"
+}
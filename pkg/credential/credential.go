@@ -0,0 +1,129 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package credential obtains credentials for auth types which must be
+// acquired at request time, such as OAuth2 access tokens or tokens
+// obtained from another request
+package credential
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Carbonfrost/pastiche/pkg/contextual"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+// Cache obtains credentials and caches them in a directory so that they
+// can be reused between invocations
+type Cache struct {
+	dir    string
+	client *http.Client
+	now    func() time.Time
+	fetch  SpecFetcher
+	scope  SpecScope
+
+	// tokens memoizes the tokens for specs by their cache file
+	tokens map[string]string
+}
+
+// Option configures the cache
+type Option func(*Cache)
+
+// NewCache creates a cache which stores credentials in the given directory
+func NewCache(dir string, opts ...Option) *Cache {
+	c := &Cache{
+		dir:    dir,
+		client: http.DefaultClient,
+		now:    time.Now,
+		tokens: map[string]string{},
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// WithHTTPClient sets the HTTP client used to obtain credentials
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Cache) {
+		c.client = client
+	}
+}
+
+// WithClock sets the function used to get the current time
+func WithClock(now func() time.Time) Option {
+	return func(c *Cache) {
+		c.now = now
+	}
+}
+
+// Resolve obtains credentials for the auth using the cache in the workspace
+// directory. Auth types that don't need to be acquired are returned as is.
+// Tokens for specs are obtained using the fetcher in the context.
+func Resolve(ctx context.Context, a model.Auth) (model.Auth, error) {
+	if !needsResolve(a) {
+		return a, nil
+	}
+	return NewCache(
+		contextual.Workspace(ctx).CacheDir(),
+		withSpecContext(ctx),
+	).Resolve(ctx, a)
+}
+
+// Resolve obtains credentials for the auth. Auth types that don't need to
+// be acquired are returned as is.
+func (c *Cache) Resolve(ctx context.Context, a model.Auth) (model.Auth, error) {
+	switch auth := a.(type) {
+	case *model.OAuth2Auth:
+		token, err := c.oauth2Token(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BearerAuth{Token: token}, nil
+
+	case *model.SpecAuth:
+		token, err := c.specToken(ctx, auth)
+		if err != nil {
+			return nil, err
+		}
+		return &model.BearerAuth{Token: token}, nil
+	}
+	return a, nil
+}
+
+func needsResolve(a model.Auth) bool {
+	switch a.(type) {
+	case *model.OAuth2Auth, *model.SpecAuth:
+		return true
+	}
+	return false
+}
+
+func readCached[T any](file string) (*T, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func writeCached(file string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0600)
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credential
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Carbonfrost/pastiche/pkg/internal/log"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+// expiryDelta is how much earlier than its expiry a token is considered
+// expired, which accounts for clock skew and latency
+const expiryDelta = 30 * time.Second
+
+type oauth2Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresIn    int64     `json:"expires_in,omitempty"`
+	Expiry       time.Time `json:"expiry,omitzero"`
+}
+
+func (c *Cache) oauth2Token(ctx context.Context, a *model.OAuth2Auth) (string, error) {
+	if a.TokenURL == "" {
+		return "", fmt.Errorf("oauth2: token URL is required")
+	}
+
+	file := c.oauth2CacheFile(a)
+	cached, _ := readCached[oauth2Token](file)
+	if cached != nil && !c.expired(cached) {
+		return cached.AccessToken, nil
+	}
+
+	var (
+		token *oauth2Token
+		err   error
+	)
+
+	refreshToken := a.RefreshToken
+	if cached != nil && cached.RefreshToken != "" {
+		refreshToken = cached.RefreshToken
+	}
+
+	if refreshToken != "" {
+		token, err = c.requestToken(ctx, a, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {refreshToken},
+		})
+		if token != nil && token.RefreshToken == "" {
+			token.RefreshToken = refreshToken
+		}
+	}
+
+	// Fall back to client credentials when the refresh token could not be
+	// used or wasn't present
+	if token == nil && a.ClientSecret != "" {
+		token, err = c.requestToken(ctx, a, url.Values{
+			"grant_type": {"client_credentials"},
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+	if token == nil {
+		return "", fmt.Errorf("oauth2: client secret or refresh token is required")
+	}
+
+	if err := writeCached(file, token); err != nil {
+		log.Warn(err)
+	}
+	return token.AccessToken, nil
+}
+
+func (c *Cache) requestToken(ctx context.Context, a *model.OAuth2Auth, form url.Values) (*oauth2Token, error) {
+	form.Set("client_id", a.ClientID)
+	if a.ClientSecret != "" {
+		form.Set("client_secret", a.ClientSecret)
+	}
+	if len(a.Scopes) > 0 {
+		form.Set("scope", strings.Join(a.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth2: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("oauth2: token endpoint returned %s", resp.Status)
+	}
+
+	var token oauth2Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("oauth2: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("oauth2: token endpoint returned no access token")
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = c.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+func (c *Cache) expired(t *oauth2Token) bool {
+	if t.Expiry.IsZero() {
+		return false
+	}
+	return c.now().Add(expiryDelta).After(t.Expiry)
+}
+
+// oauth2CacheFile gets the name of the file which caches the token, which
+// is keyed by the token endpoint, client, and scopes
+func (c *Cache) oauth2CacheFile(a *model.OAuth2Auth) string {
+	h := sha256.New()
+	for _, s := range []string{a.TokenURL, a.ClientID, strings.Join(a.Scopes, " ")} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return filepath.Join(c.dir, "oauth2", hex.EncodeToString(h.Sum(nil))[:32]+".json")
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credential_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/Carbonfrost/pastiche/pkg/credential"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OAuth2", func() {
+
+	var (
+		server   *httptest.Server
+		requests []url.Values
+		now      time.Time
+		status   int
+
+		clock = func() time.Time { return now }
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		status = http.StatusOK
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = r.ParseForm()
+			requests = append(requests, r.PostForm)
+			if status != http.StatusOK {
+				w.WriteHeader(status)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  fmt.Sprintf("token-%d", len(requests)),
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+				"refresh_token": fmt.Sprintf("refresh-%d", len(requests)),
+			})
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	var resolve = func(cache *credential.Cache, a model.Auth) (model.Auth, error) {
+		return cache.Resolve(context.Background(), a)
+	}
+
+	It("uses the client credentials grant", func() {
+		cache := credential.NewCache(GinkgoT().TempDir(), credential.WithClock(clock))
+		auth, err := resolve(cache, &model.OAuth2Auth{
+			TokenURL:     server.URL,
+			ClientID:     "id",
+			ClientSecret: "secret",
+			Scopes:       []string{"read", "write"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+		Expect(requests).To(ConsistOf(url.Values{
+			"grant_type":    {"client_credentials"},
+			"client_id":     {"id"},
+			"client_secret": {"secret"},
+			"scope":         {"read write"},
+		}))
+	})
+
+	It("uses the cached token until it expires", func() {
+		dir := GinkgoT().TempDir()
+		a := &model.OAuth2Auth{TokenURL: server.URL, ClientID: "id", ClientSecret: "secret"}
+
+		auth, _ := resolve(credential.NewCache(dir, credential.WithClock(clock)), a)
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+
+		now = now.Add(30 * time.Minute)
+		auth, _ = resolve(credential.NewCache(dir, credential.WithClock(clock)), a)
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+		Expect(requests).To(HaveLen(1))
+	})
+
+	It("refreshes the token when it expires", func() {
+		dir := GinkgoT().TempDir()
+		a := &model.OAuth2Auth{TokenURL: server.URL, ClientID: "id", ClientSecret: "secret"}
+
+		_, _ = resolve(credential.NewCache(dir, credential.WithClock(clock)), a)
+
+		now = now.Add(time.Hour)
+		auth, err := resolve(credential.NewCache(dir, credential.WithClock(clock)), a)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-2"}))
+		Expect(requests[1]).To(Equal(url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {"refresh-1"},
+			"client_id":     {"id"},
+			"client_secret": {"secret"},
+		}))
+	})
+
+	It("uses the configured refresh token", func() {
+		cache := credential.NewCache(GinkgoT().TempDir(), credential.WithClock(clock))
+		auth, err := resolve(cache, &model.OAuth2Auth{
+			TokenURL:     server.URL,
+			ClientID:     "id",
+			RefreshToken: "configured",
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+		Expect(requests[0]).To(HaveKeyWithValue("refresh_token", []string{"configured"}))
+	})
+
+	It("returns an error when the token endpoint fails", func() {
+		status = http.StatusUnauthorized
+		cache := credential.NewCache(GinkgoT().TempDir(), credential.WithClock(clock))
+		_, err := resolve(cache, &model.OAuth2Auth{
+			TokenURL:     server.URL,
+			ClientID:     "id",
+			ClientSecret: "secret",
+		})
+
+		Expect(err).To(MatchError("oauth2: token endpoint returned 401 Unauthorized"))
+	})
+
+	It("returns other auth as is", func() {
+		cache := credential.NewCache(GinkgoT().TempDir())
+		basic := &model.BasicAuth{User: "u"}
+		Expect(resolve(cache, basic)).To(BeIdenticalTo(basic))
+	})
+})
@@ -0,0 +1,151 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credential
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/pastiche/pkg/internal/log"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+// SpecFetcher runs the request for the spec of the auth and extracts the
+// token from its response
+type SpecFetcher func(ctx context.Context, a *model.SpecAuth) (string, error)
+
+type contextKey string
+
+const (
+	fetcherKey contextKey = "pastiche.credential.fetcher"
+	pendingKey contextKey = "pastiche.credential.pending"
+)
+
+type specToken struct {
+	Token  string    `json:"token"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// SpecScope gets the scope of the tokens for specs, such as the name of the
+// selected server, which is part of the key used to cache them
+type SpecScope func(ctx context.Context) string
+
+// specContext is stored in the context so that tokens are shared by all of
+// the requests of the command
+type specContext struct {
+	fetch  SpecFetcher
+	scope  SpecScope
+	tokens map[string]string
+}
+
+// ContextValue provides an action which sets the fetcher used to obtain
+// tokens for specs into the context. Tokens are cached by the scope and
+// are fetched at most once for the remainder of the command.
+func ContextValue(f SpecFetcher, scope SpecScope) cli.Action {
+	return cli.WithContextValue(fetcherKey, &specContext{
+		fetch:  f,
+		scope:  scope,
+		tokens: map[string]string{},
+	})
+}
+
+// WithSpecFetcher sets the fetcher used to obtain tokens for specs
+func WithSpecFetcher(f SpecFetcher) Option {
+	return func(c *Cache) {
+		c.fetch = f
+	}
+}
+
+// WithSpecScope sets the function which gets the scope of tokens for specs
+func WithSpecScope(s SpecScope) Option {
+	return func(c *Cache) {
+		c.scope = s
+	}
+}
+
+func withSpecContext(ctx context.Context) Option {
+	return func(c *Cache) {
+		if s, ok := ctx.Value(fetcherKey).(*specContext); ok {
+			c.fetch = s.fetch
+			c.scope = s.scope
+			c.tokens = s.tokens
+		}
+	}
+}
+
+func (c *Cache) specToken(ctx context.Context, a *model.SpecAuth) (string, error) {
+	if c.fetch == nil {
+		return "", fmt.Errorf("fromSpec: no fetcher available for %q", a.Spec)
+	}
+
+	// Detect when the request for the spec itself requires the same spec
+	pending, _ := ctx.Value(pendingKey).([]string)
+	if slices.Contains(pending, a.Spec) {
+		return "", fmt.Errorf("fromSpec: circular reference to %q", a.Spec)
+	}
+	ctx = context.WithValue(ctx, pendingKey, append(slices.Clip(pending), a.Spec))
+
+	file := c.specCacheFile(ctx, a)
+	if token, ok := c.tokens[file]; ok {
+		return token, nil
+	}
+
+	if a.TTL <= 0 {
+		token, err := c.fetch(ctx, a)
+		if err != nil {
+			return "", err
+		}
+		c.tokens[file] = token
+		return token, nil
+	}
+
+	cached, _ := readCached[specToken](file)
+	if cached != nil && c.now().Before(cached.Expiry) {
+		c.tokens[file] = cached.Token
+		return cached.Token, nil
+	}
+
+	token, err := c.fetch(ctx, a)
+	if err != nil {
+		return "", err
+	}
+	c.tokens[file] = token
+
+	err = writeCached(file, &specToken{
+		Token:  token,
+		Expiry: c.now().Add(a.TTL),
+	})
+	if err != nil {
+		log.Warn(err)
+	}
+	return token, nil
+}
+
+// specCacheFile gets the name of the file which caches the token, which
+// is keyed by the spec, its scope, and the extract filter
+func (c *Cache) specCacheFile(ctx context.Context, a *model.SpecAuth) string {
+	extract, _ := json.Marshal(a.Extract)
+
+	var scope string
+	if c.scope != nil {
+		scope = c.scope(ctx)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(a.Spec))
+	h.Write([]byte{0})
+	h.Write([]byte(scope))
+	h.Write([]byte{0})
+	fmt.Fprintf(h, "%T", a.Extract)
+	h.Write(extract)
+	return filepath.Join(c.dir, "spec", hex.EncodeToString(h.Sum(nil))[:32]+".json")
+}
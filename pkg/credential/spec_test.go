@@ -0,0 +1,131 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package credential_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Carbonfrost/pastiche/pkg/credential"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SpecAuth", func() {
+
+	var (
+		now     time.Time
+		fetched int
+		dir     string
+
+		clock = func() time.Time { return now }
+		fetch = func(_ context.Context, a *model.SpecAuth) (string, error) {
+			fetched++
+			return fmt.Sprintf("token-%d", fetched), nil
+		}
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		fetched = 0
+		dir = GinkgoT().TempDir()
+	})
+
+	var resolve = func(a model.Auth) (model.Auth, error) {
+		cache := credential.NewCache(dir, credential.WithClock(clock), credential.WithSpecFetcher(fetch))
+		return cache.Resolve(context.Background(), a)
+	}
+
+	It("provides the fetched token as bearer", func() {
+		auth, err := resolve(&model.SpecAuth{Spec: "@corp/login.session"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+	})
+
+	It("fetches each time when there is no TTL", func() {
+		a := &model.SpecAuth{Spec: "@corp/login.session"}
+		_, _ = resolve(a)
+		auth, _ := resolve(a)
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-2"}))
+	})
+
+	It("caches the token for the TTL", func() {
+		a := &model.SpecAuth{Spec: "@corp/login.session", TTL: 10 * time.Minute}
+		_, _ = resolve(a)
+
+		now = now.Add(5 * time.Minute)
+		auth, _ := resolve(a)
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+
+		now = now.Add(5 * time.Minute)
+		auth, _ = resolve(a)
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-2"}))
+	})
+
+	It("caches tokens by extract filter", func() {
+		_, _ = resolve(&model.SpecAuth{
+			Spec:    "@corp/login.session",
+			Extract: &model.JMESPathOutput{Query: "token"},
+			TTL:     time.Minute,
+		})
+		auth, _ := resolve(&model.SpecAuth{
+			Spec:    "@corp/login.session",
+			Extract: &model.JMESPathOutput{Query: "refresh"},
+			TTL:     time.Minute,
+		})
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-2"}))
+	})
+
+	It("fetches at most once for the same cache", func() {
+		a := &model.SpecAuth{Spec: "@corp/login.session"}
+		cache := credential.NewCache(dir, credential.WithClock(clock), credential.WithSpecFetcher(fetch))
+		_, _ = cache.Resolve(context.Background(), a)
+		auth, _ := cache.Resolve(context.Background(), a)
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+	})
+
+	It("caches tokens by scope", func() {
+		a := &model.SpecAuth{Spec: "@corp/login.session", TTL: time.Minute}
+		scope := "staging"
+		resolve := func() (model.Auth, error) {
+			cache := credential.NewCache(dir,
+				credential.WithClock(clock),
+				credential.WithSpecFetcher(fetch),
+				credential.WithSpecScope(func(context.Context) string { return scope }),
+			)
+			return cache.Resolve(context.Background(), a)
+		}
+
+		_, _ = resolve()
+		scope = "production"
+		auth, _ := resolve()
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-2"}))
+
+		scope = "staging"
+		auth, _ = resolve()
+		Expect(auth).To(Equal(&model.BearerAuth{Token: "token-1"}))
+	})
+
+	It("returns an error on circular reference", func() {
+		a := &model.SpecAuth{Spec: "@corp/login.session"}
+		var cache *credential.Cache
+		cache = credential.NewCache(dir, credential.WithSpecFetcher(func(ctx context.Context, a *model.SpecAuth) (string, error) {
+			_, err := cache.Resolve(ctx, a)
+			return "", err
+		}))
+
+		_, err := cache.Resolve(context.Background(), a)
+		Expect(err).To(MatchError(`fromSpec: circular reference to "@corp/login.session"`))
+	})
+
+	It("returns an error when there is no fetcher", func() {
+		cache := credential.NewCache(dir)
+		_, err := cache.Resolve(context.Background(), &model.SpecAuth{Spec: "@corp/login.session"})
+		Expect(err).To(MatchError(`fromSpec: no fetcher available for "@corp/login.session"`))
+	})
+})
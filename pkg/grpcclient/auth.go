@@ -7,6 +7,8 @@ package grpcclient
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"strings"
 
 	"github.com/Carbonfrost/pastiche/pkg/model"
 	"google.golang.org/grpc"
@@ -25,20 +27,55 @@ func (b *headerAuthCreds) RequireTransportSecurity() bool {
 }
 
 func withAuth(a model.Auth) grpc.DialOption {
-	if a == nil {
+	headers := authHeaders(a)
+	if headers == nil {
 		return nil
 	}
+	return grpc.WithPerRPCCredentials(&headerAuthCreds{headers: headers})
+}
+
+// checkAuth rejects the auth types which sign or respond to challenges to HTTP
+// requests, which are only supported by the HTTP client
+func checkAuth(a model.Auth) error {
+	switch a.(type) {
+	case *model.DigestAuth:
+		return errors.New("digest auth is not supported by the gRPC client")
+	case *model.AWSSigV4Auth:
+		return errors.New("awsSigV4 auth is not supported by the gRPC client")
+	}
+	return nil
+}
+
+func authHeaders(a model.Auth) map[string]string {
 	switch auth := a.(type) {
 	case *model.BasicAuth:
 		return basicAuth(auth.User, auth.Password)
+	case *model.BearerAuth:
+		return map[string]string{
+			"authorization": "Bearer " + auth.Token,
+		}
+	case *model.APIKeyAuth:
+		return apiKeyAuth(auth)
 	}
 	return nil
 }
 
-func basicAuth(username, password string) grpc.DialOption {
+func basicAuth(username, password string) map[string]string {
 	auth := username + ":" + password
 	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-	return grpc.WithPerRPCCredentials(&headerAuthCreds{headers: map[string]string{
+	return map[string]string{
 		"authorization": "Basic " + encodedAuth,
-	}})
+	}
+}
+
+func apiKeyAuth(auth *model.APIKeyAuth) map[string]string {
+	// gRPC has no query string, so the key is always sent as metadata,
+	// using the name of the query parameter if that was specified
+	name := auth.HeaderName()
+	if auth.Header == "" && auth.Query != "" {
+		name = auth.Query
+	}
+	return map[string]string{
+		strings.ToLower(name): auth.Key,
+	}
 }
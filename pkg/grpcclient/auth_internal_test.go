@@ -0,0 +1,53 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("authHeaders", func() {
+
+	DescribeTable("examples", func(a model.Auth, expected map[string]string) {
+		Expect(authHeaders(a)).To(Equal(expected))
+	},
+		Entry("nil", nil, nil),
+		Entry("basic",
+			&model.BasicAuth{User: "u", Password: "p"},
+			map[string]string{"authorization": "Basic dTpw"}),
+		Entry("bearer",
+			&model.BearerAuth{Token: "t"},
+			map[string]string{"authorization": "Bearer t"}),
+		Entry("API key default",
+			&model.APIKeyAuth{Key: "k"},
+			map[string]string{"x-api-key": "k"}),
+		Entry("API key header",
+			&model.APIKeyAuth{Key: "k", Header: "X-Token"},
+			map[string]string{"x-token": "k"}),
+		Entry("API key query uses name as metadata",
+			&model.APIKeyAuth{Key: "k", Query: "api_key"},
+			map[string]string{"api_key": "k"}),
+	)
+})
+
+var _ = Describe("checkAuth", func() {
+
+	DescribeTable("examples", func(a model.Auth, expected types.GomegaMatcher) {
+		Expect(checkAuth(a)).To(expected)
+	},
+		Entry("nil", nil, Succeed()),
+		Entry("bearer", &model.BearerAuth{Token: "t"}, Succeed()),
+		Entry("digest",
+			&model.DigestAuth{User: "u", Password: "p"},
+			MatchError("digest auth is not supported by the gRPC client")),
+		Entry("AWS SigV4",
+			&model.AWSSigV4Auth{Region: "us-east-1"},
+			MatchError("awsSigV4 auth is not supported by the gRPC client")),
+	)
+})
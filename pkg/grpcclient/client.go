@@ -5,10 +5,12 @@
 package grpcclient
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -16,6 +18,7 @@ import (
 	"github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
 	joetls "github.com/Carbonfrost/joe-cli-http/tls"
+	"github.com/Carbonfrost/pastiche/pkg/credential"
 	"github.com/Carbonfrost/pastiche/pkg/internal/build"
 	"github.com/Carbonfrost/pastiche/pkg/model"
 	"github.com/fullstorydev/grpcurl"
@@ -23,6 +26,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
@@ -35,9 +39,11 @@ type Client struct {
 	address string
 	symbol  string
 
-	protoset          []string
-	disableReflection bool
-	plaintext         bool
+	settings
+
+	// flags contains the settings from flags, which are kept so that the
+	// client configuration of one location does not apply to the next
+	flags *settings
 
 	body              io.ReadCloser
 	headers           []string
@@ -45,7 +51,26 @@ type Client struct {
 	auth              model.Auth // TODO Would be better to use package-owned auth
 
 	// interop with httpclient
-	locationResolver httpclient.LocationResolver
+	locationResolver     httpclient.LocationResolver
+	downloaderMiddleware []func(context.Context, httpclient.Downloader) httpclient.Downloader
+}
+
+// settings are the options of the connection and calls, which are set from
+// flags and the client configuration of the location
+type settings struct {
+	protoset          []string
+	disableReflection bool
+	preferProtoset    bool
+	plaintext         bool
+	tlsConfig         *tls.Config
+	authority         string
+	serverName        string
+	dialTimeout       time.Duration
+	deadline          time.Duration
+	keepalive         time.Duration
+	maxRecvMsgSize    int
+	maxSendMsgSize    int
+	encoding          string
 }
 
 type modelLocation interface {
@@ -62,6 +87,8 @@ type contextKey string
 
 const servicesKey contextKey = "grpcclient_services"
 
+const defaultDialTimeout = 10 * time.Second
+
 var (
 	defaultOpts = []Option{
 		WithDefaultAction(),
@@ -106,7 +133,8 @@ func (c *Client) Do(ctx context.Context) ([]*Response, error) {
 		// TODO Read document from correct source
 		c.body = os.Stdin
 
-		resp, err := fetchAndPrintCore(ctx, c, c.address, c.symbol)
+		u := &url.URL{Scheme: "grpc", Host: c.address, Path: "/" + c.symbol}
+		resp, err := fetchAndPrintCore(ctx, c, u)
 		if err != nil {
 			return nil, err
 		}
@@ -118,10 +146,20 @@ func (c *Client) Do(ctx context.Context) ([]*Response, error) {
 }
 
 func (c *Client) doOne(ctx context.Context, l httpclient.Location) (*Response, error) {
-	uctx, u, err := l.URL(ctx)
+	uctx, u, err := c.prepare(ctx, l)
 	if err != nil {
 		return nil, err
 	}
+	return fetchAndPrintCore(uctx, c, u)
+}
+
+// prepare applies the options, headers, body, and auth from the location
+// to the client and gets its URL
+func (c *Client) prepare(ctx context.Context, l httpclient.Location) (context.Context, *url.URL, error) {
+	uctx, u, err := l.URL(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// TODO Like httpclient, it would be better to communicate with the underlying
 	// layer via Middleware or another convention rather than depend upon model.
@@ -133,19 +171,33 @@ func (c *Client) doOne(ctx context.Context, l httpclient.Location) (*Response, e
 
 		request, err := m.Resolved().EvalRequest(nil, c.vars())
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		auth, err := credential.Resolve(ctx, request.Auth)
+		if err != nil {
+			return nil, nil, err
 		}
+		if err := checkAuth(auth); err != nil {
+			return nil, nil, err
+		}
+
 		c.headers = formatHeaders(request.Headers)
 		c.body = request.Body
-		c.auth = request.Auth
+		c.auth = auth
 	}
 
-	address := u.Host
-	symbol := strings.TrimPrefix(u.Path, "/")
-	return fetchAndPrintCore(uctx, c, address, symbol)
+	return uctx, u, nil
 }
 
+// copyOpts applies the client configuration of the location to the settings
+// from flags
 func (c *Client) copyOpts(clientOpts model.Client) {
+	if c.flags == nil {
+		flags := c.settings
+		c.flags = &flags
+	}
+	c.settings = *c.flags
+
 	opts, _ := clientOpts.(*model.GRPCClient)
 	if opts == nil {
 		return
@@ -156,6 +208,17 @@ func (c *Client) copyOpts(clientOpts model.Client) {
 	if opts.ProtoSet != "" {
 		c.protoset = []string{opts.ProtoSet}
 	}
+
+	// Values set from flags take precedence
+	c.preferProtoset = c.preferProtoset || opts.PreferProtoSet
+	c.authority = cmp.Or(c.authority, opts.Authority)
+	c.serverName = cmp.Or(c.serverName, opts.ServerName)
+	c.dialTimeout = cmp.Or(c.dialTimeout, opts.DialTimeout)
+	c.deadline = cmp.Or(c.deadline, opts.Deadline)
+	c.keepalive = cmp.Or(c.keepalive, opts.Keepalive)
+	c.maxRecvMsgSize = cmp.Or(c.maxRecvMsgSize, opts.MaxRecvMessageSize)
+	c.maxSendMsgSize = cmp.Or(c.maxSendMsgSize, opts.MaxSendMessageSize)
+	c.encoding = cmp.Or(c.encoding, opts.Encoding)
 }
 
 func (c *Client) vars() map[string]any {
@@ -194,12 +257,26 @@ func WithLocationResolver(value httpclient.LocationResolver) Option {
 	}
 }
 
+// WithDownloaderMiddleware adds middleware to the downloader which writes
+// the response, which is compatible with the middleware of httpclient
+func WithDownloaderMiddleware(m func(context.Context, httpclient.Downloader) httpclient.Downloader) Option {
+	return func(c *Client) {
+		c.downloaderMiddleware = append(c.downloaderMiddleware, m)
+	}
+}
+
 func WithDisableReflection(value bool) Option {
 	return func(c *Client) {
 		c.disableReflection = value
 	}
 }
 
+func WithPreferProtoset(value bool) Option {
+	return func(c *Client) {
+		c.preferProtoset = value
+	}
+}
+
 func WithProtoset(value string) Option {
 	return func(c *Client) {
 		c.protoset = append(c.protoset, value)
@@ -212,6 +289,77 @@ func WithPlaintext(value bool) Option {
 	}
 }
 
+// WithTLSConfig sets the TLS configuration, which is otherwise obtained
+// from the context
+func WithTLSConfig(value *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = value
+	}
+}
+
+// WithAuthority sets the value of the :authority pseudo-header, which
+// is otherwise the address of the server
+func WithAuthority(value string) Option {
+	return func(c *Client) {
+		c.authority = value
+	}
+}
+
+// WithServerName sets the server name used to verify the certificate of the
+// server, which is otherwise the authority or the address of the server
+func WithServerName(value string) Option {
+	return func(c *Client) {
+		c.serverName = value
+	}
+}
+
+// WithDialTimeout sets the maximum time to wait for the connection to the
+// server to be established
+func WithDialTimeout(value time.Duration) Option {
+	return func(c *Client) {
+		c.dialTimeout = value
+	}
+}
+
+// WithDeadline sets the maximum time that each call can take
+func WithDeadline(value time.Duration) Option {
+	return func(c *Client) {
+		c.deadline = value
+	}
+}
+
+// WithKeepalive sets the time after which keepalive pings are sent when the
+// connection is inactive
+func WithKeepalive(value time.Duration) Option {
+	return func(c *Client) {
+		c.keepalive = value
+	}
+}
+
+// WithMaxRecvMessageSize sets the maximum size in bytes of messages that can
+// be received
+func WithMaxRecvMessageSize(value int) Option {
+	return func(c *Client) {
+		c.maxRecvMsgSize = value
+	}
+}
+
+// WithMaxSendMessageSize sets the maximum size in bytes of messages that can
+// be sent
+func WithMaxSendMessageSize(value int) Option {
+	return func(c *Client) {
+		c.maxSendMsgSize = value
+	}
+}
+
+// WithEncoding sets the encoding of messages sent using gRPC-Web and
+// Connect, which is either json or proto
+func WithEncoding(value string) Option {
+	return func(c *Client) {
+		c.encoding = value
+	}
+}
+
 func WithAddr(value string) Option {
 	return func(c *Client) {
 		c.address = value
@@ -236,68 +384,109 @@ func (o Option) Execute(c context.Context) error {
 }
 
 func (c *Client) dial(ctx context.Context, target string) (*grpc.ClientConn, error) {
-	// TODO Should support customized dial and connect timeouts
-	dialTime := 10 * time.Second
-	ctx, cancel := context.WithTimeout(ctx, dialTime)
+	ctx, cancel := context.WithTimeout(ctx, cmp.Or(c.dialTimeout, defaultDialTimeout))
 	defer cancel()
 
-	var opts []grpc.DialOption
 	var creds credentials.TransportCredentials
+	if !c.plaintext {
+		creds = credentials.NewTLS(c.tlsClientConfig(ctx))
+	}
 
-	if c.plaintext {
-		// TODO Should support configuring authority
+	cc, err := grpcurl.BlockingDial(ctx, "", target, creds, c.dialOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial target host %q: %w", target, err)
+	}
+	return cc, nil
+}
 
-	} else {
-		tlsConf := clientTLSConfig(ctx)
-		creds = credentials.NewTLS(tlsConf)
+// tlsClientConfig gets the TLS configuration used to connect. Unless the
+// server name is set, the certificate is verified using the authority, which
+// is set as a dial option, or the address of the server.
+func (c *Client) tlsClientConfig(ctx context.Context) *tls.Config {
+	tlsConf := c.tlsConfig
+	if tlsConf == nil {
+		tlsConf = clientTLSConfig(ctx)
 	}
-	if c.auth != nil {
-		opts = append(opts, withAuth(c.auth))
+	if c.serverName != "" {
+		tlsConf = tlsConf.Clone()
+		tlsConf.ServerName = c.serverName
 	}
+	return tlsConf
+}
 
-	opts = append(opts, grpc.WithUserAgent(build.DefaultUserAgent()))
+func (c *Client) dialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+	if auth := withAuth(c.auth); auth != nil {
+		opts = append(opts, auth)
+	}
+	if c.authority != "" {
+		opts = append(opts, grpc.WithAuthority(c.authority))
+	}
+	if c.keepalive > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    c.keepalive,
+			Timeout: c.keepalive,
+		}))
+	}
 
-	cc, err := grpcurl.BlockingDial(ctx, "", target, creds, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to dial target host %q: %w", target, err)
+	var callOpts []grpc.CallOption
+	if c.maxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(c.maxRecvMsgSize))
 	}
-	return cc, nil
+	if c.maxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(c.maxSendMsgSize))
+	}
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	return append(opts, grpc.WithUserAgent(build.DefaultUserAgent()))
 }
 
-func (c *Client) descSource(ctx context.Context, target string) (grpcurl.DescriptorSource, error) {
-	var fileSource grpcurl.DescriptorSource
+// descSource gets the source of descriptors, which uses server reflection
+// over the connection and the protoset files. By default, reflection is
+// tried first, falling back to the protoset files unless they are preferred.
+// The cleanup function releases the reflection stream.
+func (c *Client) descSource(ctx context.Context, cc *grpc.ClientConn) (grpcurl.DescriptorSource, func(), error) {
+	var sources compositeSource
 	if len(c.protoset) > 0 {
-		var err error
-		fileSource, err = grpcurl.DescriptorSourceFromProtoSets(c.protoset...)
+		fileSource, err := grpcurl.DescriptorSourceFromProtoSets(c.protoset...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to process proto descriptor sets: %w", err)
+			return nil, nil, fmt.Errorf("failed to process proto descriptor sets: %w", err)
 		}
+		sources = append(sources, fileSource)
 	}
 
+	cleanup := func() {}
 	if !c.disableReflection {
 		md := grpcurl.MetadataFromHeaders(append(c.headers, c.reflectionHeaders...))
 		refCtx := metadata.NewOutgoingContext(ctx, md)
 
-		cc, err := c.dial(ctx, target)
-		if err != nil {
-			return nil, fmt.Errorf("failed to reflect service: %w", err)
-		}
-
 		refClient := grpcreflect.NewClientAuto(refCtx, cc)
 		refClient.AllowMissingFileDescriptors()
-		reflSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
-		if fileSource != nil {
-			panic("not implemented: composite reflection and file source")
+		cleanup = refClient.Reset
 
+		reflSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
+		if c.preferProtoset {
+			sources = append(sources, reflSource)
 		} else {
-			return reflSource, nil
+			sources = append(compositeSource{reflSource}, sources...)
 		}
-	} else {
-		return fileSource, nil
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, nil, fmt.Errorf("protoset is required when reflection is disabled")
+	case 1:
+		return sources[0], cleanup, nil
+	default:
+		return sources, cleanup, nil
 	}
 }
 
-func fetchAndPrintCore(ctx context.Context, c *Client, target, methodName string) (*Response, error) {
+func fetchAndPrintCore(ctx context.Context, c *Client, u *url.URL) (*Response, error) {
+	target := u.Host
+	methodName := strings.TrimPrefix(u.Path, "/")
 	options := grpcurl.FormatOptions{
 		EmitJSONDefaultFields: true,
 		IncludeTextSeparator:  true,
@@ -307,17 +496,22 @@ func fetchAndPrintCore(ctx context.Context, c *Client, target, methodName string
 	var addlHeaders []string
 	addlHeaders = append(addlHeaders, c.headers...)
 
-	in := c.body
+	var in io.Reader = c.body
+	if c.body != nil {
+		in = newMessageReader(c.body)
+	}
 
 	cc, err := c.dial(ctx, target)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial target host %q: %w", target, err)
 	}
+	defer cc.Close()
 
-	descSource, err := c.descSource(ctx, target)
+	descSource, cleanup, err := c.descSource(ctx, cc)
 	if err != nil {
 		return nil, err
 	}
+	defer cleanup()
 
 	rf, formatter, err := grpcurl.RequestParserAndFormatter(
 		grpcurl.Format(grpcurl.FormatJSON),
@@ -327,18 +521,29 @@ func fetchAndPrintCore(ctx context.Context, c *Client, target, methodName string
 		return nil, fmt.Errorf("failed to construct request parser and formatter: %w", err)
 	}
 
-	eventHandler := &grpcurl.DefaultEventHandler{
-		Out:            os.Stdout,
-		Formatter:      formatter,
-		VerbosityLevel: 0,
+	// The deadline only applies to the call, which excludes downloading the
+	// response so that the context of the handler is preserved
+	callCtx := ctx
+	if c.deadline > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, c.deadline)
+		defer cancel()
 	}
 
-	// TODO Actually provide response and filter support
-	err = grpcurl.InvokeRPC(ctx, descSource, cc, methodName, addlHeaders, eventHandler, rf.Next)
+	eventHandler := newResponseHandler(ctx, c, u, formatter)
+	err = grpcurl.InvokeRPC(callCtx, descSource, cc, methodName, addlHeaders, eventHandler, rf.Next)
+	if err != nil {
+		return nil, handleStatus(err)
+	}
+
+	resp, err := eventHandler.finish()
+	if err != nil {
+		return nil, err
+	}
 	if eventHandler.Status.Code() != codes.OK {
 		fmt.Fprintln(os.Stderr, eventHandler.Status)
 	}
-	return nil, handleStatus(err)
+	return resp, nil
 }
 
 func clientTLSConfig(c context.Context) *tls.Config {
@@ -351,41 +556,48 @@ func handleStatus(err error) error {
 	}
 
 	if stat, ok := status.FromError(err); ok {
-		if stat.Code() != codes.OK {
-			w := os.Stderr
-			formatter := grpcurl.NewTextFormatter(true)
-			fmt.Fprintf(w, "ERROR:\n  Code: %s\n  Message: %s\n", stat.Code().String(), stat.Message())
-
-			statpb := stat.Proto()
-			if len(statpb.Details) > 0 {
-				fmt.Fprintf(w, "  Details:\n")
-				for i, det := range statpb.Details {
-					prefix := fmt.Sprintf("  %d)", i+1)
-					fmt.Fprintf(w, "%s\t", prefix)
-					prefix = strings.Repeat(" ", len(prefix)) + "\t"
-
-					output, err := formatter(det)
-					if err != nil {
-						fmt.Fprintf(w, "Error parsing detail message: %v\n", err)
+		return printStatus(os.Stderr, stat)
+	}
+	return nil
+}
+
+// printStatus prints a status which is not OK and returns the error which
+// exits with a code derived from the status code
+func printStatus(w io.Writer, stat *status.Status) error {
+	if stat.Code() == codes.OK {
+		return nil
+	}
+
+	formatter := grpcurl.NewTextFormatter(true)
+	fmt.Fprintf(w, "ERROR:\n  Code: %s\n  Message: %s\n", stat.Code().String(), stat.Message())
 
+	statpb := stat.Proto()
+	if len(statpb.Details) > 0 {
+		fmt.Fprintf(w, "  Details:\n")
+		for i, det := range statpb.Details {
+			prefix := fmt.Sprintf("  %d)", i+1)
+			fmt.Fprintf(w, "%s\t", prefix)
+			prefix = strings.Repeat(" ", len(prefix)) + "\t"
+
+			output, err := formatter(det)
+			if err != nil {
+				fmt.Fprintf(w, "Error parsing detail message: %v\n", err)
+
+			} else {
+				lines := strings.Split(output, "\n")
+				for i, line := range lines {
+					if i == 0 {
+						// first line is already indented
+						fmt.Fprintf(w, "%s\n", line)
 					} else {
-						lines := strings.Split(output, "\n")
-						for i, line := range lines {
-							if i == 0 {
-								// first line is already indented
-								fmt.Fprintf(w, "%s\n", line)
-							} else {
-								fmt.Fprintf(w, "%s%s\n", prefix, line)
-							}
-						}
+						fmt.Fprintf(w, "%s%s\n", prefix, line)
 					}
 				}
 			}
-
-			return cli.Exit(2 + int(stat.Code()))
 		}
 	}
-	return nil
+
+	return cli.Exit(2 + int(stat.Code()))
 }
 
 var _ cli.Action = Option(nil)
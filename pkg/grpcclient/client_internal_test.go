@@ -0,0 +1,97 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"time"
+
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("copyOpts", func() {
+
+	opts := &model.GRPCClient{
+		Authority:          "api.example.com",
+		ServerName:         "tls.example.com",
+		DialTimeout:        5 * time.Second,
+		Deadline:           30 * time.Second,
+		Keepalive:          time.Minute,
+		MaxRecvMessageSize: 1024,
+		MaxSendMessageSize: 2048,
+	}
+
+	It("copies the connection options", func() {
+		c := New()
+		c.copyOpts(opts)
+
+		Expect(c.authority).To(Equal("api.example.com"))
+		Expect(c.serverName).To(Equal("tls.example.com"))
+		Expect(c.dialTimeout).To(Equal(5 * time.Second))
+		Expect(c.deadline).To(Equal(30 * time.Second))
+		Expect(c.keepalive).To(Equal(time.Minute))
+		Expect(c.maxRecvMsgSize).To(Equal(1024))
+		Expect(c.maxSendMsgSize).To(Equal(2048))
+	})
+
+	It("prefers the options set from flags", func() {
+		c := New(
+			WithAuthority("proxy.example.com"),
+			WithDeadline(time.Second),
+			WithMaxRecvMessageSize(4096),
+		)
+		c.copyOpts(opts)
+
+		Expect(c.authority).To(Equal("proxy.example.com"))
+		Expect(c.deadline).To(Equal(time.Second))
+		Expect(c.maxRecvMsgSize).To(Equal(4096))
+		Expect(c.dialTimeout).To(Equal(5 * time.Second))
+	})
+
+	It("prefers protoset files when set from flags", func() {
+		c := New(WithPreferProtoset(true))
+		c.copyOpts(opts)
+
+		Expect(c.preferProtoset).To(BeTrue())
+	})
+
+	It("does not apply options to the next location", func() {
+		c := New(WithDeadline(time.Second))
+		c.copyOpts(opts)
+		c.copyOpts(&model.GRPCClient{DialTimeout: time.Minute})
+
+		Expect(c.authority).To(BeEmpty())
+		Expect(c.serverName).To(BeEmpty())
+		Expect(c.deadline).To(Equal(time.Second))
+		Expect(c.dialTimeout).To(Equal(time.Minute))
+		Expect(c.maxRecvMsgSize).To(BeZero())
+	})
+
+	It("resets options when the location has no client configuration", func() {
+		c := New(WithProtoset("a.protoset"))
+		c.copyOpts(&model.GRPCClient{ProtoSet: "b.protoset", Authority: "api.example.com"})
+		c.copyOpts(nil)
+
+		Expect(c.protoset).To(Equal([]string{"a.protoset"}))
+		Expect(c.authority).To(BeEmpty())
+	})
+})
+
+var _ = Describe("tlsClientConfig", func() {
+
+	It("does not use the authority as the server name", func() {
+		c := New(WithTLSConfig(&tls.Config{}), WithAuthority("api.example.com"))
+		Expect(c.tlsClientConfig(context.Background()).ServerName).To(BeEmpty())
+	})
+
+	It("uses the server name", func() {
+		c := New(WithTLSConfig(&tls.Config{}), WithAuthority("api.example.com"), WithServerName("tls.example.com"))
+		Expect(c.tlsClientConfig(context.Background()).ServerName).To(Equal("tls.example.com"))
+	})
+})
@@ -0,0 +1,83 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+)
+
+// compositeSource is a descriptor source which combines several sources.
+// Symbols are found using the first source that provides them, whereas
+// services and extensions are combined from all of the sources.
+type compositeSource []grpcurl.DescriptorSource
+
+func (s compositeSource) ListServices() ([]string, error) {
+	var (
+		res  []string
+		errs []error
+	)
+	for _, src := range s {
+		services, err := src.ListServices()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, svc := range services {
+			if !slices.Contains(res, svc) {
+				res = append(res, svc)
+			}
+		}
+	}
+	if len(errs) == len(s) {
+		return nil, errors.Join(errs...)
+	}
+	slices.Sort(res)
+	return res, nil
+}
+
+func (s compositeSource) FindSymbol(fullyQualifiedName string) (desc.Descriptor, error) {
+	var firstErr error
+	for _, src := range s {
+		d, err := src.FindSymbol(fullyQualifiedName)
+		if err == nil {
+			return d, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+func (s compositeSource) AllExtensionsForType(typeName string) ([]*desc.FieldDescriptor, error) {
+	var (
+		res  []*desc.FieldDescriptor
+		errs []error
+	)
+	for _, src := range s {
+		exts, err := src.AllExtensionsForType(typeName)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, ext := range exts {
+			if !slices.ContainsFunc(res, func(f *desc.FieldDescriptor) bool {
+				return f.GetNumber() == ext.GetNumber()
+			}) {
+				res = append(res, ext)
+			}
+		}
+	}
+	if len(errs) == len(s) {
+		return nil, errors.Join(errs...)
+	}
+	return res, nil
+}
+
+var _ grpcurl.DescriptorSource = compositeSource(nil)
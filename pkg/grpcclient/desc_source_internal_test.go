@@ -0,0 +1,85 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"errors"
+
+	"github.com/jhump/protoreflect/desc"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeSource struct {
+	services []string
+	symbols  map[string]desc.Descriptor
+	err      error
+}
+
+func (f fakeSource) ListServices() ([]string, error) {
+	return f.services, f.err
+}
+
+func (f fakeSource) FindSymbol(name string) (desc.Descriptor, error) {
+	if d, ok := f.symbols[name]; ok {
+		return d, nil
+	}
+	return nil, errors.New("symbol not found: " + name)
+}
+
+func (f fakeSource) AllExtensionsForType(string) ([]*desc.FieldDescriptor, error) {
+	return nil, f.err
+}
+
+var _ = Describe("compositeSource", func() {
+
+	message, _ := desc.LoadMessageDescriptor("grpc.reflection.v1.ServerReflectionRequest")
+
+	Describe("ListServices", func() {
+
+		It("combines services from the sources", func() {
+			s := compositeSource{
+				fakeSource{services: []string{"b.Service", "a.Service"}},
+				fakeSource{services: []string{"a.Service", "c.Service"}},
+			}
+			Expect(s.ListServices()).To(Equal([]string{"a.Service", "b.Service", "c.Service"}))
+		})
+
+		It("ignores sources which fail", func() {
+			s := compositeSource{
+				fakeSource{err: errors.New("reflection unavailable")},
+				fakeSource{services: []string{"a.Service"}},
+			}
+			Expect(s.ListServices()).To(Equal([]string{"a.Service"}))
+		})
+
+		It("returns error when all sources fail", func() {
+			s := compositeSource{
+				fakeSource{err: errors.New("reflection unavailable")},
+			}
+			_, err := s.ListServices()
+			Expect(err).To(MatchError("reflection unavailable"))
+		})
+	})
+
+	Describe("FindSymbol", func() {
+
+		It("falls back to subsequent sources", func() {
+			s := compositeSource{
+				fakeSource{},
+				fakeSource{symbols: map[string]desc.Descriptor{"grpc.reflection.v1.ServerReflectionRequest": message}},
+			}
+			Expect(message).NotTo(BeNil())
+			Expect(s.FindSymbol("grpc.reflection.v1.ServerReflectionRequest")).To(BeIdenticalTo(message))
+		})
+
+		It("returns error from first source when not found", func() {
+			s := compositeSource{fakeSource{}, fakeSource{}}
+			_, err := s.FindSymbol("a.Missing")
+			Expect(err).To(MatchError("symbol not found: a.Missing"))
+		})
+	})
+})
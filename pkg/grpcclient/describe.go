@@ -0,0 +1,177 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/grpc"
+)
+
+// Description describes a symbol such as a service, method, or message
+type Description struct {
+	Name            string          `json:"name"`
+	Kind            string          `json:"kind"`
+	Methods         []string        `json:"methods,omitempty"`
+	Input           string          `json:"input,omitempty"`
+	Output          string          `json:"output,omitempty"`
+	ClientStreaming bool            `json:"clientStreaming,omitempty"`
+	ServerStreaming bool            `json:"serverStreaming,omitempty"`
+	Definition      string          `json:"definition"`
+	RequestTemplate json.RawMessage `json:"requestTemplate,omitempty"`
+}
+
+// ListServices lists the services available from the server or its
+// protoset files
+func (c *Client) ListServices(ctx context.Context) ([]string, error) {
+	var res []string
+	err := c.withDescSource(ctx, func(source grpcurl.DescriptorSource) error {
+		var err error
+		res, err = source.ListServices()
+		return err
+	})
+	return res, err
+}
+
+// Describe describes the symbol that has the given fully-qualified name. The
+// name of a method can also be specified using the Package.Service/Method form
+// used in URIs. For methods and messages, the description includes a JSON
+// template of the request message.
+func (c *Client) Describe(ctx context.Context, symbol string) (*Description, error) {
+	var res *Description
+	err := c.withDescSource(ctx, func(source grpcurl.DescriptorSource) error {
+		var err error
+		res, err = describe(source, symbolName(symbol))
+		return err
+	})
+	return res, err
+}
+
+func describe(source grpcurl.DescriptorSource, name string) (*Description, error) {
+	d, err := source.FindSymbol(name)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := grpcurl.GetDescriptorText(d, source)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Description{
+		Name:       d.GetFullyQualifiedName(),
+		Definition: text,
+	}
+
+	var template *desc.MessageDescriptor
+	switch d := d.(type) {
+	case *desc.ServiceDescriptor:
+		res.Kind = "service"
+		for _, m := range d.GetMethods() {
+			res.Methods = append(res.Methods, m.GetName())
+		}
+	case *desc.MethodDescriptor:
+		res.Kind = "method"
+		res.Input = d.GetInputType().GetFullyQualifiedName()
+		res.Output = d.GetOutputType().GetFullyQualifiedName()
+		res.ClientStreaming = d.IsClientStreaming()
+		res.ServerStreaming = d.IsServerStreaming()
+		template = d.GetInputType()
+	case *desc.MessageDescriptor:
+		res.Kind = "message"
+		template = d
+	case *desc.EnumDescriptor:
+		res.Kind = "enum"
+	case *desc.EnumValueDescriptor:
+		res.Kind = "enum value"
+	case *desc.FieldDescriptor:
+		res.Kind = "field"
+	case *desc.OneOfDescriptor:
+		res.Kind = "oneof"
+	case *desc.FileDescriptor:
+		res.Kind = "file"
+	}
+
+	if template != nil {
+		res.RequestTemplate, err = requestTemplate(source, template)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// requestTemplate provides a JSON message with all of the fields, which can
+// be used as the body of an endpoint
+func requestTemplate(source grpcurl.DescriptorSource, md *desc.MessageDescriptor) (json.RawMessage, error) {
+	_, formatter, err := grpcurl.RequestParserAndFormatter(
+		grpcurl.FormatJSON,
+		source,
+		strings.NewReader(""),
+		grpcurl.FormatOptions{EmitJSONDefaultFields: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := formatter(grpcurl.MakeTemplate(md))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template for %s: %w", md.GetFullyQualifiedName(), err)
+	}
+	return json.RawMessage(text), nil
+}
+
+// withDescSource calls the function with the source of descriptors for the
+// location of the client. The server is only dialed when reflection is used.
+func (c *Client) withDescSource(ctx context.Context, fn func(grpcurl.DescriptorSource) error) error {
+	ctx, u, err := c.location(ctx)
+	if err != nil {
+		return err
+	}
+
+	var cc *grpc.ClientConn
+	if !c.disableReflection {
+		cc, err = c.dial(ctx, u.Host)
+		if err != nil {
+			return err
+		}
+		defer cc.Close()
+	}
+
+	source, cleanup, err := c.descSource(ctx, cc)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	return fn(source)
+}
+
+// location gets the URL of the first location, or the address when there is
+// no location resolver
+func (c *Client) location(ctx context.Context) (context.Context, *url.URL, error) {
+	if c.locationResolver == nil {
+		return ctx, &url.URL{Scheme: "grpc", Host: c.address, Path: "/" + c.symbol}, nil
+	}
+
+	locations, err := c.locationResolver.Resolve(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(locations) == 0 {
+		return nil, nil, fmt.Errorf("no location to connect to")
+	}
+	return c.prepare(ctx, locations[0])
+}
+
+func symbolName(s string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(s, "."), "/", ".")
+}
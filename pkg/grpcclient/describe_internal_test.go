@@ -0,0 +1,68 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"github.com/jhump/protoreflect/desc"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("describe", func() {
+
+	message, _ := desc.LoadMessageDescriptor("grpc.reflection.v1.ServerReflectionRequest")
+	service := message.GetFile().FindService("grpc.reflection.v1.ServerReflection")
+	method := service.FindMethodByName("ServerReflectionInfo")
+
+	source := fakeSource{symbols: map[string]desc.Descriptor{
+		"grpc.reflection.v1.ServerReflection":                      service,
+		"grpc.reflection.v1.ServerReflection.ServerReflectionInfo": method,
+		"grpc.reflection.v1.ServerReflectionRequest":               message,
+	}}
+
+	It("describes service", func() {
+		d, err := describe(source, "grpc.reflection.v1.ServerReflection")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Kind).To(Equal("service"))
+		Expect(d.Methods).To(Equal([]string{"ServerReflectionInfo"}))
+		Expect(d.Definition).To(HavePrefix("service ServerReflection {"))
+		Expect(d.RequestTemplate).To(BeNil())
+	})
+
+	It("describes method with template of its input", func() {
+		d, err := describe(source, "grpc.reflection.v1.ServerReflection.ServerReflectionInfo")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Kind).To(Equal("method"))
+		Expect(d.Input).To(Equal("grpc.reflection.v1.ServerReflectionRequest"))
+		Expect(d.Output).To(Equal("grpc.reflection.v1.ServerReflectionResponse"))
+		Expect(d.ClientStreaming).To(BeTrue())
+		Expect(d.ServerStreaming).To(BeTrue())
+		Expect(d.RequestTemplate).To(MatchJSON(`{
+			"host": "",
+			"fileContainingExtension": {"containingType": "", "extensionNumber": 0}
+		}`))
+	})
+
+	It("describes message with template", func() {
+		d, err := describe(source, "grpc.reflection.v1.ServerReflectionRequest")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(d.Kind).To(Equal("message"))
+		Expect(d.RequestTemplate).NotTo(BeNil())
+	})
+
+	It("returns error when symbol is not found", func() {
+		_, err := describe(source, "a.Missing")
+		Expect(err).To(MatchError("symbol not found: a.Missing"))
+	})
+
+	DescribeTable("symbolName", func(symbol string, expected string) {
+		Expect(symbolName(symbol)).To(Equal(expected))
+	},
+		Entry("fully-qualified name", "a.Service.Method", "a.Service.Method"),
+		Entry("URI form", "a.Service/Method", "a.Service.Method"),
+		Entry("leading dot", ".a.Message", "a.Message"),
+	)
+})
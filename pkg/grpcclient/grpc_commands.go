@@ -7,6 +7,7 @@ package grpcclient
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
@@ -47,6 +48,15 @@ func FlagsAndArgs() cli.Action {
 				{Uses: SetPlaintext()},
 				{Uses: SetDisableReflection()},
 				{Uses: SetProtoset()},
+				{Uses: SetPreferProtoset()},
+				{Uses: SetAuthority()},
+				{Uses: SetServerName()},
+				{Uses: SetDialTimeout()},
+				{Uses: SetDeadline()},
+				{Uses: SetKeepalive()},
+				{Uses: SetMaxRecvMessageSize()},
+				{Uses: SetMaxSendMessageSize()},
+				{Uses: SetEncoding()},
 			}...,
 		),
 		cli.AddArgs(
@@ -88,6 +98,114 @@ func SetDisableReflection(s ...bool) cli.Action {
 	)
 }
 
+func SetPreferProtoset(s ...bool) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "prefer-protoset",
+			HelpText: "Use the protoset schema before server gRPC schema reflection",
+			Category: requestOptions,
+		},
+		bindAction(WithPreferProtoset, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetAuthority(s ...string) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "authority",
+			HelpText: "Set the :authority pseudo-header to {AUTHORITY} instead of the server address",
+			Category: requestOptions,
+		},
+		bindAction(WithAuthority, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetServerName(s ...string) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "servername",
+			HelpText: "Verify the server certificate using {NAME} instead of the authority",
+			Category: requestOptions,
+		},
+		bindAction(WithServerName, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetDialTimeout(s ...time.Duration) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "dial-timeout",
+			HelpText: "Wait at most {DURATION} to connect to the grpc server",
+			Category: requestOptions,
+		},
+		bindAction(WithDialTimeout, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetDeadline(s ...time.Duration) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "deadline",
+			HelpText: "Allow at most {DURATION} for the call to complete",
+			Category: requestOptions,
+		},
+		bindAction(WithDeadline, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetKeepalive(s ...time.Duration) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "keepalive",
+			HelpText: "Send keepalive pings after {DURATION} of inactivity",
+			Category: requestOptions,
+		},
+		bindAction(WithKeepalive, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetMaxRecvMessageSize(s ...int) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "max-recv-msg-size",
+			HelpText: "Allow messages up to {BYTES} to be received",
+			Category: requestOptions,
+		},
+		bindAction(WithMaxRecvMessageSize, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetMaxSendMessageSize(s ...int) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "max-send-msg-size",
+			HelpText: "Allow messages up to {BYTES} to be sent",
+			Category: requestOptions,
+		},
+		bindAction(WithMaxSendMessageSize, bind.Exact(s...)),
+		tagged,
+	)
+}
+
+func SetEncoding(s ...string) cli.Action {
+	return cli.Pipeline(
+		&cli.Prototype{
+			Name:     "web-encoding",
+			HelpText: "Encode gRPC-Web and Connect messages using {ENCODING}, either json or proto",
+			Category: requestOptions,
+		},
+		bindAction(WithEncoding, bind.Exact(s...)),
+		tagged,
+	)
+}
+
 // TODO joe@futures should allow this to be typed as File
 
 func SetProtoset(s ...string) cli.Action {
@@ -0,0 +1,161 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/fullstorydev/grpcurl"
+	"github.com/golang/protobuf/proto"                           //lint:ignore SA1019 required by grpcurl.DescriptorSourceFromFileDescriptorSet
+	descpb "github.com/golang/protobuf/protoc-gen-go/descriptor" //lint:ignore SA1019 required by grpcurl.DescriptorSourceFromFileDescriptorSet
+	"github.com/jhump/protoreflect/desc"
+)
+
+// IsProtoSet detects whether the data is a protoset, which is a serialized
+// FileDescriptorSet
+func IsProtoSet(data []byte) bool {
+	fds, err := parseProtoSet(data)
+	if err != nil || len(fds.File) == 0 {
+		return false
+	}
+	for _, f := range fds.File {
+		if !strings.HasSuffix(f.GetName(), ".proto") {
+			return false
+		}
+	}
+	return true
+}
+
+// DescriptorSourceFromProtoSet gets the source of descriptors from the data
+// of a protoset
+func DescriptorSourceFromProtoSet(data []byte) (grpcurl.DescriptorSource, error) {
+	fds, err := parseProtoSet(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse protoset: %w", err)
+	}
+	return grpcurl.DescriptorSourceFromFileDescriptorSet(fds)
+}
+
+// ImportProtoSet creates the configuration of a service from the data of a
+// protoset like ToConfig. When the file which contains the protoset is
+// specified, it is recorded in the configuration and server reflection is
+// disabled.
+func ImportProtoSet(data []byte, name, file string) (*config.Service, error) {
+	source, err := DescriptorSourceFromProtoSet(data)
+	if err != nil {
+		return nil, err
+	}
+	res, err := ToConfig(source, name)
+	if err != nil {
+		return nil, err
+	}
+	if file != "" {
+		res.Client.GRPC.ProtoSet = file
+		res.Client.GRPC.DisableReflection = true
+	}
+	return res, nil
+}
+
+// ImportConfig creates the configuration of a service from the services
+// available from the server or its protoset files
+func (c *Client) ImportConfig(ctx context.Context, name string) (*config.Service, error) {
+	var res *config.Service
+	err := c.withDescSource(ctx, func(source grpcurl.DescriptorSource) error {
+		var err error
+		res, err = ToConfig(source, name)
+		return err
+	})
+	return res, err
+}
+
+// ToConfig creates the configuration of a service which has a resource for
+// each gRPC service provided by the source, each of which has a resource for
+// each of its methods. Resources for services are named using their package
+// so that services in different packages don't collide. The body of the
+// endpoint of a method is a template of its request message. The reflection
+// services are omitted.
+func ToConfig(source grpcurl.DescriptorSource, name string) (*config.Service, error) {
+	services, err := source.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &config.Service{
+		Name: name,
+		Client: &config.Client{
+			GRPC: &config.GRPCClient{},
+		},
+	}
+	for _, s := range services {
+		if strings.HasPrefix(s, "grpc.reflection.") {
+			continue
+		}
+
+		d, err := source.FindSymbol(s)
+		if err != nil {
+			return nil, err
+		}
+		sd, ok := d.(*desc.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a service", s)
+		}
+
+		r, err := serviceResource(source, sd)
+		if err != nil {
+			return nil, err
+		}
+		res.Resources = append(res.Resources, r)
+	}
+	return res, nil
+}
+
+func serviceResource(source grpcurl.DescriptorSource, sd *desc.ServiceDescriptor) (config.Resource, error) {
+	res := config.Resource{
+		Name: config.Identifier(sd.GetFullyQualifiedName()),
+		Metadata: config.Metadata{
+			Description: comments(sd),
+		},
+	}
+
+	for _, md := range sd.GetMethods() {
+		template, err := requestTemplate(source, md.GetInputType())
+		if err != nil {
+			return res, err
+		}
+
+		var body any
+		if err := json.Unmarshal(template, &body); err != nil {
+			return res, err
+		}
+
+		res.Resources = append(res.Resources, config.Resource{
+			Name: config.Identifier(md.GetName()),
+			URI:  sd.GetFullyQualifiedName() + "/" + md.GetName(),
+			Metadata: config.Metadata{
+				Description: comments(md),
+			},
+			Post: &config.Endpoint{
+				Body: body,
+			},
+		})
+	}
+	return res, nil
+}
+
+func comments(d desc.Descriptor) string {
+	return strings.TrimSpace(d.GetSourceInfo().GetLeadingComments())
+}
+
+func parseProtoSet(data []byte) (*descpb.FileDescriptorSet, error) {
+	var fds descpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &fds); err != nil {
+		return nil, err
+	}
+	return &fds, nil
+}
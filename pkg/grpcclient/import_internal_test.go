@@ -0,0 +1,81 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/golang/protobuf/proto"                           //lint:ignore SA1019 required by grpcurl.DescriptorSourceFromFileDescriptorSet
+	descpb "github.com/golang/protobuf/protoc-gen-go/descriptor" //lint:ignore SA1019 required by grpcurl.DescriptorSourceFromFileDescriptorSet
+	"github.com/jhump/protoreflect/desc"
+	_ "google.golang.org/grpc/health/grpc_health_v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ToConfig", func() {
+
+	var protoset []byte
+
+	BeforeEach(func() {
+		health, err := desc.LoadFileDescriptor("grpc/health/v1/health.proto")
+		Expect(err).NotTo(HaveOccurred())
+		reflection, err := desc.LoadFileDescriptor("grpc/reflection/v1/reflection.proto")
+		Expect(err).NotTo(HaveOccurred())
+
+		protoset, err = proto.Marshal(&descpb.FileDescriptorSet{
+			File: []*descpb.FileDescriptorProto{
+				health.AsFileDescriptorProto(),
+				reflection.AsFileDescriptorProto(),
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("detects protoset", func() {
+		Expect(IsProtoSet(protoset)).To(BeTrue())
+		Expect(IsProtoSet([]byte(`{"log": {}}`))).To(BeFalse())
+		Expect(IsProtoSet(nil)).To(BeFalse())
+	})
+
+	It("creates resources for services and methods", func() {
+		source, err := DescriptorSourceFromProtoSet(protoset)
+		Expect(err).NotTo(HaveOccurred())
+
+		svc, err := ToConfig(source, "health")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(svc.Name).To(Equal("health"))
+		Expect(svc.Client).To(Equal(&config.Client{GRPC: &config.GRPCClient{}}))
+		Expect(svc.Resources).To(HaveLen(1))
+
+		r := svc.Resources[0]
+		Expect(r.Name).To(Equal("grpc-health-v1-Health"))
+		Expect(r.Resources).To(HaveLen(3))
+		Expect(r.Resources[0]).To(Equal(config.Resource{
+			Name: "Check",
+			URI:  "grpc.health.v1.Health/Check",
+			Post: &config.Endpoint{
+				Body: map[string]any{"service": ""},
+			},
+		}))
+		Expect(r.Resources[2].URI).To(Equal("grpc.health.v1.Health/Watch"))
+	})
+
+	It("records the protoset file", func() {
+		svc, err := ImportProtoSet(protoset, "health", "health.protoset")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc.Client.GRPC).To(Equal(&config.GRPCClient{
+			ProtoSet:          "health.protoset",
+			DisableReflection: true,
+		}))
+	})
+
+	It("uses reflection when the protoset file is not specified", func() {
+		svc, err := ImportProtoSet(protoset, "health", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(svc.Client.GRPC).To(Equal(&config.GRPCClient{}))
+	})
+})
@@ -0,0 +1,45 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// messageReader reads the request messages from a stream of JSON values such
+// as a JSONL file. A value which is a list provides one message for each of
+// its elements. Messages are read as they are needed so that messages can be
+// streamed from stdin.
+type messageReader struct {
+	dec *json.Decoder
+	buf bytes.Buffer
+}
+
+func newMessageReader(r io.Reader) io.Reader {
+	return &messageReader{
+		dec: json.NewDecoder(r),
+	}
+}
+
+func (m *messageReader) Read(p []byte) (int, error) {
+	for m.buf.Len() == 0 {
+		var msg json.RawMessage
+		if err := m.dec.Decode(&msg); err != nil {
+			return 0, err
+		}
+
+		var list []json.RawMessage
+		if json.Unmarshal(msg, &list) != nil {
+			list = []json.RawMessage{msg}
+		}
+		for _, item := range list {
+			m.buf.Write(item)
+			m.buf.WriteByte('\n')
+		}
+	}
+	return m.buf.Read(p)
+}
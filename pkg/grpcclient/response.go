@@ -0,0 +1,218 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/fullstorydev/grpcurl"
+	"github.com/golang/protobuf/proto" //lint:ignore SA1019 required by grpcurl.InvocationEventHandler
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Stream describes the response of a server streaming call which is
+// being downloaded
+type Stream struct {
+	// Start is the time that the call started
+	Start time.Time
+
+	// Index is the index of the message in the stream. The final response,
+	// which provides the trailers and status, has the index after the last
+	// message.
+	Index int
+}
+
+// responseHandler captures the events of an invocation so that they can be
+// provided as responses. The messages of server streaming calls are
+// downloaded as they arrive; otherwise, the message is downloaded with the
+// trailers once the call completes.
+type responseHandler struct {
+	*grpcurl.DefaultEventHandler
+
+	ctx    context.Context
+	client *Client
+	url    *url.URL
+	start  time.Time
+
+	streaming      bool
+	requestHeader  metadata.MD
+	responseHeader metadata.MD
+	trailer        metadata.MD
+	messages       [][]byte
+	err            error
+}
+
+const streamKey contextKey = "grpcclient_stream"
+
+// StreamFromContext gets the stream from the context used to download the
+// response of a server streaming call
+func StreamFromContext(ctx context.Context) (*Stream, bool) {
+	s, ok := ctx.Value(streamKey).(*Stream)
+	return s, ok
+}
+
+func withStream(ctx context.Context, s *Stream) context.Context {
+	return context.WithValue(ctx, streamKey, s)
+}
+
+func newResponseHandler(ctx context.Context, c *Client, u *url.URL, formatter grpcurl.Formatter) *responseHandler {
+	return &responseHandler{
+		DefaultEventHandler: &grpcurl.DefaultEventHandler{
+			Out:       io.Discard,
+			Formatter: formatter,
+		},
+		ctx:    ctx,
+		client: c,
+		url:    u,
+		start:  time.Now(),
+	}
+}
+
+func (h *responseHandler) OnResolveMethod(md *desc.MethodDescriptor) {
+	h.streaming = md.IsServerStreaming()
+	h.DefaultEventHandler.OnResolveMethod(md)
+}
+
+func (h *responseHandler) OnSendHeaders(md metadata.MD) {
+	h.requestHeader = md
+	h.DefaultEventHandler.OnSendHeaders(md)
+}
+
+func (h *responseHandler) OnReceiveHeaders(md metadata.MD) {
+	h.responseHeader = md
+	h.DefaultEventHandler.OnReceiveHeaders(md)
+}
+
+func (h *responseHandler) OnReceiveResponse(resp proto.Message) {
+	index := h.NumResponses
+	h.NumResponses++
+	if h.err != nil {
+		return
+	}
+
+	data, err := h.Formatter(resp)
+	if err != nil {
+		h.err = fmt.Errorf("failed to format response message %d: %w", h.NumResponses, err)
+		return
+	}
+	if !h.streaming {
+		h.messages = append(h.messages, []byte(data))
+		return
+	}
+
+	h.err = h.client.download(h.streamContext(index), h.response([]byte(data)))
+}
+
+func (h *responseHandler) OnReceiveTrailers(stat *status.Status, md metadata.MD) {
+	h.trailer = md
+	h.DefaultEventHandler.OnReceiveTrailers(stat, md)
+}
+
+// finish downloads the final response once the call completes, which
+// contains the message of unary calls and the trailers
+func (h *responseHandler) finish() (*Response, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	var data []byte
+	if len(h.messages) > 0 {
+		data = h.messages[0]
+	}
+
+	ctx := h.ctx
+	if h.streaming {
+		ctx = h.streamContext(h.NumResponses)
+	}
+
+	resp := h.response(data)
+	return resp, h.client.download(ctx, resp)
+}
+
+func (h *responseHandler) streamContext(index int) context.Context {
+	return withStream(h.ctx, &Stream{
+		Start: h.start,
+		Index: index,
+	})
+}
+
+// response provides the message as a response so that it can be processed
+// by downloaders like any HTTP response. Once the call completes, the status
+// code is the gRPC status code, and the status is also provided in the
+// trailers as it is on the wire. Because the messages are transcoded, the
+// content type of the response is JSON.
+func (h *responseHandler) response(data []byte) *Response {
+	header := toHTTPHeader(h.responseHeader)
+	header.Set("Content-Type", "application/json")
+
+	var trailer http.Header
+	if h.Status != nil {
+		trailer = toHTTPHeader(h.trailer)
+		trailer.Set("Grpc-Status", strconv.Itoa(int(h.Status.Code())))
+		if msg := h.Status.Message(); msg != "" {
+			trailer.Set("Grpc-Message", msg)
+		}
+	}
+
+	return &Response{
+		Response: &http.Response{
+			Status:     h.Status.Code().String(),
+			StatusCode: int(h.Status.Code()),
+			Proto:      "HTTP/2.0",
+			ProtoMajor: 2,
+			Header:     header,
+			Trailer:    trailer,
+			Body:       io.NopCloser(bytes.NewReader(data)),
+			Request: &http.Request{
+				Method: http.MethodPost,
+				URL:    h.url,
+				Header: toHTTPHeader(h.requestHeader),
+			},
+		},
+	}
+}
+
+// download writes the response to the output of the command using the
+// downloader and its middleware
+func (c *Client) download(ctx context.Context, resp *Response) error {
+	stdout := io.Writer(os.Stdout)
+	if cctx, ok := cli.TryFromContext(ctx); ok {
+		stdout = cctx.Stdout
+	}
+
+	d := httpclient.NewDownloaderTo(stdout)
+	for _, m := range c.downloaderMiddleware {
+		d = m(ctx, d)
+	}
+
+	w, err := d.OpenDownload(ctx, resp)
+	if err != nil {
+		return err
+	}
+	if err := resp.CopyTo(w); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func toHTTPHeader(md metadata.MD) http.Header {
+	res := http.Header{}
+	for k, v := range md {
+		res[http.CanonicalHeaderKey(k)] = v
+	}
+	return res
+}
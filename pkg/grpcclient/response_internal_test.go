@@ -0,0 +1,142 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/golang/protobuf/proto" //lint:ignore SA1019 required by grpcurl.InvocationEventHandler
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+type download struct {
+	data   string
+	stream *Stream
+	resp   *Response
+}
+
+var _ = Describe("responseHandler", func() {
+
+	var (
+		downloads []download
+		h         *responseHandler
+		u         *url.URL
+	)
+
+	BeforeEach(func() {
+		downloads = nil
+		c := New(WithDownloaderMiddleware(func(ctx context.Context, _ httpclient.Downloader) httpclient.Downloader {
+			return recordDownloader(func(r *Response, data string) {
+				s, _ := StreamFromContext(ctx)
+				downloads = append(downloads, download{data, s, r})
+			})
+		}))
+
+		u, _ = url.Parse("grpc://localhost:8080/example.Greeter/SayHello")
+		messages := []string{`{"a": 1}`, `{"a": 2}`}
+		h = newResponseHandler(context.Background(), c, u, func(proto.Message) (string, error) {
+			msg := messages[0]
+			messages = messages[1:]
+			return msg, nil
+		})
+	})
+
+	It("downloads the message of unary calls with the trailers", func() {
+		h.OnSendHeaders(metadata.Pairs("authorization", "Bearer t"))
+		h.OnReceiveHeaders(metadata.Pairs("content-type", "application/grpc", "x-request-id", "1"))
+		h.OnReceiveResponse(nil)
+		h.OnReceiveTrailers(status.New(codes.NotFound, "missing"), metadata.Pairs("x-trailer", "t"))
+
+		resp, err := h.finish()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(downloads).To(HaveLen(1))
+		Expect(downloads[0].data).To(Equal(`{"a": 1}`))
+		Expect(downloads[0].stream).To(BeNil())
+
+		Expect(resp.Response).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":     Equal("NotFound"),
+			"StatusCode": Equal(5),
+			"Header": Equal(http.Header{
+				"Content-Type": {"application/json"},
+				"X-Request-Id": {"1"},
+			}),
+			"Trailer": Equal(http.Header{
+				"X-Trailer":    {"t"},
+				"Grpc-Status":  {"5"},
+				"Grpc-Message": {"missing"},
+			}),
+			"Request": PointTo(MatchFields(IgnoreExtras, Fields{
+				"URL":    Equal(u),
+				"Header": Equal(http.Header{"Authorization": {"Bearer t"}}),
+			})),
+		})))
+	})
+
+	It("downloads the messages of server streaming calls as they arrive", func() {
+		h.streaming = true
+		h.OnReceiveResponse(nil)
+		Expect(downloads).To(HaveLen(1))
+
+		h.OnReceiveResponse(nil)
+		h.OnReceiveTrailers(status.New(codes.OK, ""), nil)
+		_, err := h.finish()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(downloads).To(HaveLen(3))
+		Expect(downloads[0].data).To(Equal(`{"a": 1}`))
+		Expect(downloads[0].stream.Index).To(Equal(0))
+		Expect(downloads[0].resp.Trailer).To(BeNil())
+
+		Expect(downloads[1].data).To(Equal(`{"a": 2}`))
+		Expect(downloads[1].stream.Index).To(Equal(1))
+
+		Expect(downloads[2].data).To(BeEmpty())
+		Expect(downloads[2].stream.Index).To(Equal(2))
+		Expect(downloads[2].resp.Trailer).To(Equal(http.Header{"Grpc-Status": {"0"}}))
+	})
+})
+
+var _ = Describe("messageReader", func() {
+
+	DescribeTable("examples", func(body string, expected string) {
+		data, err := io.ReadAll(newMessageReader(bytes.NewBufferString(body)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(Equal(expected))
+	},
+		Entry("single message", `{"a": 1}`, "{\"a\": 1}\n"),
+		Entry("JSONL", "{\"a\": 1}\n{\"a\": 2}\n", "{\"a\": 1}\n{\"a\": 2}\n"),
+		Entry("list", `[{"a": 1}, {"a": 2}]`, "{\"a\": 1}\n{\"a\": 2}\n"),
+		Entry("empty list", `[]`, ""),
+		Entry("empty", ``, ""),
+	)
+})
+
+type recordDownloader func(*Response, string)
+
+func (r recordDownloader) OpenDownload(_ context.Context, resp *Response) (io.WriteCloser, error) {
+	return &recordWriter{record: r, resp: resp}, nil
+}
+
+type recordWriter struct {
+	bytes.Buffer
+	record recordDownloader
+	resp   *Response
+}
+
+func (w *recordWriter) Close() error {
+	w.record(w.resp, w.String())
+	return nil
+}
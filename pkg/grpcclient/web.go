@@ -0,0 +1,405 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/fullstorydev/grpcurl"
+	"github.com/golang/protobuf/jsonpb" //lint:ignore SA1019 required by dynamic.Message
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Protocols which call gRPC services over HTTP
+const (
+	ProtocolGRPCWeb = "grpc-web"
+	ProtocolConnect = "connect"
+)
+
+// Encodings of the messages sent using gRPC-Web and Connect
+const (
+	EncodingJSON  = "json"
+	EncodingProto = "proto"
+)
+
+// WebCodec converts the requests and responses of gRPC-Web and Connect calls
+// so that they can be made using the HTTP client. The body of the request is
+// JSON, which is encoded and framed as required by the protocol, and messages
+// in the response are converted back to JSON. Connect calls use the unary
+// protocol.
+type WebCodec struct {
+	Protocol string
+	Encoding string
+
+	// Method describes the method being called, which is required to
+	// use the proto encoding
+	Method *desc.MethodDescriptor
+
+	// Timeout is the maximum time that the server should allow for the call
+	Timeout time.Duration
+}
+
+type webDownloader struct {
+	httpclient.Downloader
+
+	codec *WebCodec
+}
+
+type webWriter struct {
+	bytes.Buffer
+
+	codec  *WebCodec
+	output io.WriteCloser
+	resp   *httpclient.Response
+	stderr io.Writer
+}
+
+const (
+	frameHeaderSize = 5
+	trailerFlag     = 0x80
+)
+
+// WebCodec gets the codec used to call the method at the location using the
+// given protocol. Because server reflection is not available over gRPC-Web or
+// Connect, the proto encoding requires the method to be described by the
+// protoset files.
+func (c *Client) WebCodec(ctx context.Context, protocol string, l httpclient.Location) (*WebCodec, error) {
+	_, u, err := l.URL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := l.(modelLocation); ok {
+		c.copyOpts(m.Resolved().Client())
+	}
+
+	res := &WebCodec{
+		Protocol: protocol,
+		Encoding: cmp.Or(c.encoding, defaultEncoding(protocol)),
+		Timeout:  c.deadline,
+	}
+
+	switch res.Encoding {
+	case EncodingJSON:
+		return res, nil
+	case EncodingProto:
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", res.Encoding)
+	}
+
+	if len(c.protoset) == 0 {
+		return nil, fmt.Errorf("protoset is required to use the proto encoding")
+	}
+	source, err := grpcurl.DescriptorSourceFromProtoSets(c.protoset...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process proto descriptor sets: %w", err)
+	}
+
+	name := methodName(u.Path)
+	d, err := source.FindSymbol(name)
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(*desc.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a method", name)
+	}
+	res.Method = md
+	return res, nil
+}
+
+// Middleware provides the HTTP client middleware which converts the request
+func (w *WebCodec) Middleware() httpclient.MiddlewareFunc {
+	return func(r *http.Request) error {
+		var data []byte
+		if r.Body != nil {
+			var err error
+			data, err = io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		body, err := w.EncodeRequest(data)
+		if err != nil {
+			return err
+		}
+
+		r.Method = http.MethodPost
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		r.Header.Set("Content-Type", w.contentType())
+
+		switch w.Protocol {
+		case ProtocolGRPCWeb:
+			r.Header.Set("X-Grpc-Web", "1")
+			if w.Timeout > 0 {
+				r.Header.Set("Grpc-Timeout", fmt.Sprintf("%dm", w.Timeout.Milliseconds()))
+			}
+		case ProtocolConnect:
+			r.Header.Set("Connect-Protocol-Version", "1")
+			if w.Timeout > 0 {
+				r.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(w.Timeout.Milliseconds(), 10))
+			}
+		}
+		return nil
+	}
+}
+
+// DownloaderMiddleware provides the middleware of the downloader which
+// converts the messages in the response to JSON. Any status in the trailers
+// of a gRPC-Web response is provided in the trailers of the response.
+func (w *WebCodec) DownloaderMiddleware(_ context.Context, d httpclient.Downloader) httpclient.Downloader {
+	return &webDownloader{
+		Downloader: d,
+		codec:      w,
+	}
+}
+
+// EncodeRequest converts the JSON request message into the body of the
+// request
+func (w *WebCodec) EncodeRequest(data []byte) ([]byte, error) {
+	msg, err := w.encodeMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	if w.Protocol == ProtocolGRPCWeb {
+		return frame(0, msg), nil
+	}
+	return msg, nil
+}
+
+// DecodeResponse converts the body of the response into its JSON messages.
+// Each message is followed by a newline. The trailers of a gRPC-Web response
+// are returned.
+func (w *WebCodec) DecodeResponse(data []byte) ([]byte, http.Header, error) {
+	if w.Protocol != ProtocolGRPCWeb {
+		msg, err := w.decodeMessage(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		return append(msg, '\n'), nil, nil
+	}
+
+	var (
+		res     []byte
+		trailer = http.Header{}
+	)
+	for len(data) > 0 {
+		if len(data) < frameHeaderSize {
+			return nil, nil, fmt.Errorf("unexpected end of gRPC-Web frame")
+		}
+
+		flag := data[0]
+		size := binary.BigEndian.Uint32(data[1:frameHeaderSize])
+		data = data[frameHeaderSize:]
+		if uint32(len(data)) < size {
+			return nil, nil, fmt.Errorf("unexpected end of gRPC-Web frame")
+		}
+
+		payload := data[:size]
+		data = data[size:]
+
+		if flag&trailerFlag != 0 {
+			if err := parseTrailer(payload, trailer); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		msg, err := w.decodeMessage(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		res = append(res, msg...)
+		res = append(res, '\n')
+	}
+	return res, trailer, nil
+}
+
+func (w *WebCodec) encodeMessage(data []byte) ([]byte, error) {
+	if w.Encoding == EncodingJSON {
+		if len(bytes.TrimSpace(data)) == 0 {
+			return []byte("{}"), nil
+		}
+		return data, nil
+	}
+
+	msg := dynamic.NewMessage(w.Method.GetInputType())
+	if len(bytes.TrimSpace(data)) > 0 {
+		err := msg.UnmarshalJSONPB(&jsonpb.Unmarshaler{AllowUnknownFields: true}, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse request message: %w", err)
+		}
+	}
+	return msg.Marshal()
+}
+
+func (w *WebCodec) decodeMessage(data []byte) ([]byte, error) {
+	if w.Encoding == EncodingJSON {
+		return data, nil
+	}
+
+	msg := dynamic.NewMessage(w.Method.GetOutputType())
+	if err := msg.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("failed to parse response message: %w", err)
+	}
+	return msg.MarshalJSONPB(&jsonpb.Marshaler{EmitDefaults: true})
+}
+
+func (w *WebCodec) contentType() string {
+	if w.Protocol == ProtocolGRPCWeb {
+		return "application/grpc-web+" + w.Encoding
+	}
+	return "application/" + w.Encoding
+}
+
+// decodes determines whether the response has a content type that must be
+// decoded, which excludes Connect errors and JSON messages
+func (w *WebCodec) decodes(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch w.Protocol {
+	case ProtocolGRPCWeb:
+		return mt == "application/grpc-web" ||
+			mt == "application/grpc-web+proto" ||
+			mt == "application/grpc-web+json"
+	case ProtocolConnect:
+		return mt == "application/proto"
+	}
+	return false
+}
+
+func (d *webDownloader) OpenDownload(ctx context.Context, r *httpclient.Response) (io.WriteCloser, error) {
+	if !d.codec.decodes(r.Header.Get("Content-Type")) {
+		return d.Downloader.OpenDownload(ctx, r)
+	}
+
+	// Trailers are only known once the response has been decoded, so they
+	// are added to the trailers of the response when the download closes
+	r.Header.Set("Content-Type", "application/json")
+	if r.Trailer == nil {
+		r.Trailer = http.Header{}
+	}
+
+	output, err := d.Downloader.OpenDownload(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	stderr := io.Writer(os.Stderr)
+	if c, ok := cli.TryFromContext(ctx); ok {
+		stderr = c.Stderr
+	}
+	return &webWriter{
+		codec:  d.codec,
+		output: output,
+		resp:   r,
+		stderr: stderr,
+	}, nil
+}
+
+func (w *webWriter) Close() error {
+	data, trailer, err := w.codec.DecodeResponse(w.Bytes())
+	if err != nil {
+		w.output.Close()
+		return err
+	}
+	for k, v := range trailer {
+		w.resp.Trailer[k] = v
+	}
+
+	if _, err := w.output.Write(data); err != nil {
+		w.output.Close()
+		return err
+	}
+
+	if err := w.output.Close(); err != nil {
+		return err
+	}
+
+	// In trailers-only responses, the status is provided in the headers
+	return printStatus(w.stderr, webStatus(w.resp.Trailer, w.resp.Header))
+}
+
+func webStatus(headers ...http.Header) *status.Status {
+	for _, h := range headers {
+		code := h.Get("Grpc-Status")
+		if code == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			return status.Newf(codes.Unknown, "invalid grpc-status %q", code)
+		}
+		msg, err := url.PathUnescape(h.Get("Grpc-Message"))
+		if err != nil {
+			msg = h.Get("Grpc-Message")
+		}
+		return status.New(codes.Code(n), msg)
+	}
+	return status.New(codes.OK, "")
+}
+
+func parseTrailer(data []byte, trailer http.Header) error {
+	// Ensure the final header is terminated so that it can be read as MIME
+	// headers
+	r := textproto.NewReader(bufio.NewReader(io.MultiReader(
+		bytes.NewReader(bytes.TrimSpace(data)),
+		strings.NewReader("\r\n\r\n"),
+	)))
+	h, err := r.ReadMIMEHeader()
+	if err != nil {
+		return fmt.Errorf("failed to parse gRPC-Web trailers: %w", err)
+	}
+	for k, v := range h {
+		trailer[k] = append(trailer[k], v...)
+	}
+	return nil
+}
+
+func frame(flag byte, msg []byte) []byte {
+	res := make([]byte, frameHeaderSize, frameHeaderSize+len(msg))
+	res[0] = flag
+	binary.BigEndian.PutUint32(res[1:], uint32(len(msg)))
+	return append(res, msg...)
+}
+
+func defaultEncoding(protocol string) string {
+	// Proxies like Envoy only translate gRPC-Web which uses the proto encoding
+	if protocol == ProtocolGRPCWeb {
+		return EncodingProto
+	}
+	return EncodingJSON
+}
+
+// methodName gets the fully-qualified name of the method from the path of the
+// URL, which ends with Package.Service/Method
+func methodName(p string) string {
+	service, method := path.Split(strings.TrimSuffix(p, "/"))
+	return path.Base(service) + "." + method
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package grpcclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/Carbonfrost/joe-cli"
+	"github.com/Carbonfrost/joe-cli-http/httpclient"
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/grpc/codes"
+	_ "google.golang.org/grpc/health/grpc_health_v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WebCodec", func() {
+
+	health, _ := desc.LoadFileDescriptor("grpc/health/v1/health.proto")
+	check := health.FindService("grpc.health.v1.Health").FindMethodByName("Check")
+
+	Describe("EncodeRequest", func() {
+
+		DescribeTable("examples", func(codec *WebCodec, data string, expected []byte) {
+			actual, err := codec.EncodeRequest([]byte(data))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(expected))
+		},
+			Entry("gRPC-Web proto",
+				&WebCodec{Protocol: ProtocolGRPCWeb, Encoding: EncodingProto, Method: check},
+				`{"service": "a"}`,
+				[]byte{0, 0, 0, 0, 3, 0x0a, 1, 'a'},
+			),
+			Entry("gRPC-Web JSON",
+				&WebCodec{Protocol: ProtocolGRPCWeb, Encoding: EncodingJSON},
+				`{"service":"a"}`,
+				append([]byte{0, 0, 0, 0, 15}, `{"service":"a"}`...),
+			),
+			Entry("Connect proto",
+				&WebCodec{Protocol: ProtocolConnect, Encoding: EncodingProto, Method: check},
+				`{"service": "a"}`,
+				[]byte{0x0a, 1, 'a'},
+			),
+			Entry("Connect JSON with empty body",
+				&WebCodec{Protocol: ProtocolConnect, Encoding: EncodingJSON},
+				"",
+				[]byte("{}"),
+			),
+		)
+
+		It("returns error when message does not match the input type", func() {
+			codec := &WebCodec{Protocol: ProtocolConnect, Encoding: EncodingProto, Method: check}
+			_, err := codec.EncodeRequest([]byte(`{"service": 1}`))
+			Expect(err).To(MatchError(HavePrefix("failed to parse request message")))
+		})
+	})
+
+	Describe("DecodeResponse", func() {
+
+		It("decodes gRPC-Web messages and trailers", func() {
+			codec := &WebCodec{Protocol: ProtocolGRPCWeb, Encoding: EncodingProto, Method: check}
+			data := append(
+				frame(0, []byte{0x08, 1}),
+				frame(trailerFlag, []byte("grpc-status: 0\r\ngrpc-message: \r\n"))...,
+			)
+
+			actual, trailer, err := codec.DecodeResponse(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual)).To(Equal(`{"status":"SERVING"}` + "\n"))
+			Expect(trailer).To(HaveKeyWithValue("Grpc-Status", []string{"0"}))
+		})
+
+		It("decodes Connect message", func() {
+			codec := &WebCodec{Protocol: ProtocolConnect, Encoding: EncodingProto, Method: check}
+
+			actual, trailer, err := codec.DecodeResponse([]byte{0x08, 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(actual)).To(Equal(`{"status":"NOT_SERVING"}` + "\n"))
+			Expect(trailer).To(BeNil())
+		})
+
+		It("returns error when frame is truncated", func() {
+			codec := &WebCodec{Protocol: ProtocolGRPCWeb, Encoding: EncodingJSON}
+
+			_, _, err := codec.DecodeResponse([]byte{0, 0, 0, 0, 9, '{', '}'})
+			Expect(err).To(MatchError("unexpected end of gRPC-Web frame"))
+		})
+	})
+
+	DescribeTable("decodes", func(protocol, contentType string, expected bool) {
+		codec := &WebCodec{Protocol: protocol}
+		Expect(codec.decodes(contentType)).To(Equal(expected))
+	},
+		Entry("gRPC-Web", ProtocolGRPCWeb, "application/grpc-web", true),
+		Entry("gRPC-Web proto", ProtocolGRPCWeb, "application/grpc-web+proto", true),
+		Entry("gRPC-Web text", ProtocolGRPCWeb, "application/grpc-web-text", false),
+		Entry("Connect proto", ProtocolConnect, "application/proto", true),
+		Entry("Connect JSON", ProtocolConnect, "application/json; charset=utf-8", false),
+	)
+
+	It("gets status from trailers or headers", func() {
+		stat := webStatus(http.Header{}, http.Header{
+			"Grpc-Status":  {"5"},
+			"Grpc-Message": {"not%20found"},
+		})
+		Expect(stat.Code()).To(Equal(codes.NotFound))
+		Expect(stat.Message()).To(Equal("not found"))
+	})
+
+	Describe("webWriter", func() {
+
+		It("returns error when the status is not OK", func() {
+			var stderr bytes.Buffer
+			w := &webWriter{
+				codec:  &WebCodec{Protocol: ProtocolConnect, Encoding: EncodingJSON},
+				output: nopWriteCloser{io.Discard},
+				resp: &httpclient.Response{Response: &http.Response{
+					Header:  http.Header{"Grpc-Status": {"5"}},
+					Trailer: http.Header{},
+				}},
+				stderr: &stderr,
+			}
+			w.WriteString("{}")
+
+			err := w.Close()
+			Expect(err).To(HaveOccurred())
+			Expect(err.(cli.ExitCoder).ExitCode()).To(Equal(2 + int(codes.NotFound)))
+			Expect(stderr.String()).To(ContainSubstring("Code: NotFound"))
+		})
+	})
+
+	DescribeTable("methodName", func(path string, expected string) {
+		Expect(methodName(path)).To(Equal(expected))
+	},
+		Entry("method path", "/a.Service/Method", "a.Service.Method"),
+		Entry("prefixed path", "/api/a.Service/Method", "a.Service.Method"),
+	)
+})
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -5,6 +5,11 @@
 package model
 
 import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/Carbonfrost/joe-cli-http/uritemplates"
 	"github.com/Carbonfrost/pastiche/pkg/config"
 )
@@ -110,10 +115,15 @@ func resource(r config.Resource) *Resource {
 	if r.Patch != nil {
 		res.Endpoints = append(res.Endpoints, endpoint("PATCH", r.Patch))
 	}
+	for _, method := range slices.Sorted(maps.Keys(r.Methods)) {
+		if ep := r.Methods[method]; ep != nil {
+			res.Endpoints = append(res.Endpoints, endpoint(strings.ToUpper(method), ep))
+		}
+	}
 
 	// Implicitly create GET endpoint if none other was created
 	if len(res.Endpoints) == 0 {
-		res.Endpoints = append(res.Endpoints, &Endpoint{Method: "GET"})
+		res.Endpoints = append(res.Endpoints, endpoint("GET", &config.Endpoint{}))
 	}
 	res.Resources = resources(r.Resources)
 	return res
@@ -140,11 +150,26 @@ func endpoint(method string, r *config.Endpoint) *Endpoint {
 		Links:       links(r.Links),
 		Body:        r.Body,
 		RawBody:     r.RawBody,
+		BodyFile:    r.BodyFile,
 		Vars:        r.Vars,
 		Form:        r.Form,
 		Auth:        auth(r.Auth),
 		Output:      outputs(r.Output),
 		VarSets:     varSets(r.VarSets),
+		Expect:      expectation(r.Expect),
+	}
+}
+
+func expectation(e *config.Expect) *Expectation {
+	if e == nil {
+		return nil
+	}
+	return &Expectation{
+		Status:     e.Status,
+		Headers:    e.Headers,
+		JMESPath:   e.JMESPath,
+		XPath:      e.XPath,
+		MaxLatency: time.Duration(e.MaxLatency),
 	}
 }
 
@@ -170,9 +195,19 @@ func client(c *config.Client) Client {
 	}
 	if c.GRPC != nil {
 		return &GRPCClient{
-			DisableReflection: c.GRPC.DisableReflection,
-			ProtoSet:          c.GRPC.ProtoSet,
-			Plaintext:         c.GRPC.Plaintext,
+			DisableReflection:  c.GRPC.DisableReflection,
+			ProtoSet:           c.GRPC.ProtoSet,
+			PreferProtoSet:     c.GRPC.PreferProtoSet,
+			Plaintext:          c.GRPC.Plaintext,
+			Authority:          c.GRPC.Authority,
+			ServerName:         c.GRPC.ServerName,
+			DialTimeout:        time.Duration(c.GRPC.DialTimeout),
+			Deadline:           time.Duration(c.GRPC.Deadline),
+			Keepalive:          time.Duration(c.GRPC.Keepalive),
+			MaxRecvMessageSize: c.GRPC.MaxRecvMessageSize,
+			MaxSendMessageSize: c.GRPC.MaxSendMessageSize,
+			Protocol:           c.GRPC.Protocol,
+			Encoding:           c.GRPC.Encoding,
 		}
 	}
 	if c.HTTP != nil {
@@ -186,12 +221,64 @@ func auth(a *config.Auth) Auth {
 	if a == nil {
 		return nil
 	}
+	if a.None {
+		return &NoAuth{}
+	}
 	if a.Basic != nil {
 		return &BasicAuth{
 			User:     a.Basic.User,
 			Password: a.Basic.Password,
 		}
 	}
+	if a.Bearer != nil {
+		return &BearerAuth{
+			Token: a.Bearer.Token,
+		}
+	}
+	if a.APIKey != nil {
+		return &APIKeyAuth{
+			Key:    a.APIKey.Key,
+			Header: a.APIKey.Header,
+			Query:  a.APIKey.Query,
+		}
+	}
+	if a.OAuth2 != nil {
+		return &OAuth2Auth{
+			TokenURL:     a.OAuth2.TokenURL,
+			ClientID:     a.OAuth2.ClientID,
+			ClientSecret: a.OAuth2.ClientSecret,
+			Scopes:       a.OAuth2.Scopes,
+			RefreshToken: a.OAuth2.RefreshToken,
+		}
+	}
+	if a.Digest != nil {
+		return &DigestAuth{
+			User:     a.Digest.User,
+			Password: a.Digest.Password,
+		}
+	}
+	if a.AWSSigV4 != nil {
+		return &AWSSigV4Auth{
+			Region:          a.AWSSigV4.Region,
+			Service:         a.AWSSigV4.Service,
+			AccessKeyID:     a.AWSSigV4.AccessKeyID,
+			SecretAccessKey: a.AWSSigV4.SecretAccessKey,
+			SessionToken:    a.AWSSigV4.SessionToken,
+		}
+	}
+	if a.FromSpec != "" {
+		res := &SpecAuth{
+			Spec: a.FromSpec,
+			TTL:  time.Duration(a.TTL),
+		}
+		if a.Extract != nil {
+			res.Extract = outputFilter(config.Output{
+				JMESPath: a.Extract.JMESPath,
+				Dig:      a.Extract.Dig,
+			})
+		}
+		return res
+	}
 
 	return nil
 }
@@ -318,9 +405,25 @@ func step(s config.Step) *Step {
 		RawBody:     s.RawBody,
 		Vars:        s.Vars,
 		StepType:    stepType(s),
+		Captures:    captures(s.Captures),
+		Expect:      expectation(s.Expect),
 	}
 }
 
+func captures(caps []config.Capture) []*Capture {
+	if len(caps) == 0 {
+		return nil
+	}
+	res := make([]*Capture, len(caps))
+	for i, c := range caps {
+		res[i] = &Capture{
+			Name:   c.Name,
+			Filter: outputFilter(config.Output{JMESPath: c.JMESPath, Dig: c.Dig}),
+		}
+	}
+	return res
+}
+
 func stepType(s config.Step) StepType {
 	if s.Spec != "" {
 		return &SpecStep{
@@ -5,11 +5,14 @@
 package model
 
 import (
+	"reflect"
 	"strings"
 
 	"github.com/Carbonfrost/pastiche/pkg/config"
 )
 
+// ToConfig converts the model to its configuration. The conversion is
+// lossless, so loading the result produces an equivalent model.
 func ToConfig(m *Model) *config.File {
 	var services []config.Service
 	for _, s := range m.Services {
@@ -17,6 +20,8 @@ func ToConfig(m *Model) *config.File {
 	}
 	return &config.File{
 		Services: services,
+		VarSets:  configVarSets(m.VarSets),
+		Flows:    configFlows(m.Flows),
 	}
 }
 
@@ -25,43 +30,74 @@ func configService(v *Service) config.Service {
 	for i, s := range v.Servers {
 		servers[i] = configServer(s)
 	}
+
+	// The root resource is implicitly created when the service is loaded,
+	// so only its children are kept unless it was changed
+	var resources []config.Resource
+	if isImplicitRoot(v.Resource) {
+		resources = configResources(v.Resource.Resources)
+	} else {
+		resources = singleton(configResource(v.Resource))
+	}
+
 	return config.Service{
 		Name:      v.Name,
-		Metadata:  config.Metadata{Title: v.Title, Description: v.Description, Links: configLinks(v.Links)},
+		Metadata:  configMetadata(v.Comment, v.Title, v.Description, v.Tags, v.Links),
 		Servers:   servers,
-		Resources: singleton(configResource(v.Resource)),
+		Resources: resources,
 		Vars:      v.Vars,
 		Client:    configClient(v.Client),
+		Auth:      configAuth(v.Auth),
+		Output:    configOutputs(v.Output),
+		VarSets:   configVarSets(v.VarSets),
 	}
 }
 
 func configServer(s *Server) config.Server {
 	return config.Server{
 		Name:     s.Name,
-		Metadata: config.Metadata{Title: s.Title, Description: s.Description, Links: configLinks(s.Links)},
+		Metadata: configMetadata(s.Comment, s.Title, s.Description, s.Tags, s.Links),
 		BaseURL:  s.BaseURL,
 		Headers:  s.Headers,
+		Query:    s.Query,
 		Vars:     s.Vars,
+		Auth:     configAuth(s.Auth),
+		Output:   configOutputs(s.Output),
+		VarSets:  configVarSets(s.VarSets),
 	}
 }
 
 func configResource(r *Resource) *config.Resource {
+	if r == nil {
+		return nil
+	}
+
 	uri := ""
 	if r.URITemplate != nil {
 		uri = r.URITemplate.String()
 	}
 	res := &config.Resource{
 		Name:     r.Name,
-		Metadata: config.Metadata{Title: r.Title, Description: r.Description, Links: configLinks(r.Links)},
+		Metadata: configMetadata(r.Comment, r.Title, r.Description, r.Tags, r.Links),
 		URI:      uri,
 		Headers:  r.Headers,
+		Query:    r.Query,
 		Body:     r.Body,
 		RawBody:  r.RawBody,
 		Vars:     r.Vars,
 		Form:     r.Form,
+		Auth:     configAuth(r.Auth),
+		Output:   configOutputs(r.Output),
+		VarSets:  configVarSets(r.VarSets),
+	}
+
+	// A GET endpoint is implicitly created when there are no others
+	endpoints := r.Endpoints
+	if hasImplicitEndpoint(r) {
+		endpoints = nil
 	}
 
-	for _, e := range r.Endpoints {
+	for _, e := range endpoints {
 		ep := configEndpoint(e)
 
 		switch strings.ToLower(e.Method) {
@@ -90,7 +126,10 @@ func configResource(r *Resource) *config.Resource {
 			res.Patch = ep
 
 		default:
-			panic("not implemented: custom endpoints")
+			if res.Methods == nil {
+				res.Methods = config.Methods{}
+			}
+			res.Methods[strings.ToUpper(e.Method)] = ep
 		}
 	}
 
@@ -99,6 +138,9 @@ func configResource(r *Resource) *config.Resource {
 }
 
 func configResources(resources []*Resource) []config.Resource {
+	if len(resources) == 0 {
+		return nil
+	}
 	res := make([]config.Resource, len(resources))
 	for i, child := range resources {
 		res[i] = *configResource(child)
@@ -109,24 +151,58 @@ func configResources(resources []*Resource) []config.Resource {
 func configEndpoint(r *Endpoint) *config.Endpoint {
 	return &config.Endpoint{
 		Name:     r.Name,
-		Metadata: config.Metadata{Title: r.Title, Description: r.Description, Links: configLinks(r.Links)},
+		Metadata: configMetadata(r.Comment, r.Title, r.Description, r.Tags, r.Links),
 		Headers:  r.Headers,
+		Query:    r.Query,
 		Body:     r.Body,
 		RawBody:  r.RawBody,
+		BodyFile: r.BodyFile,
 		Vars:     r.Vars,
 		Form:     r.Form,
+		Auth:     configAuth(r.Auth),
+		Output:   configOutputs(r.Output),
+		VarSets:  configVarSets(r.VarSets),
+		Expect:   configExpect(r.Expect),
+	}
+}
+
+func configExpect(e *Expectation) *config.Expect {
+	if e == nil {
+		return nil
+	}
+	return &config.Expect{
+		Status:     e.Status,
+		Headers:    e.Headers,
+		JMESPath:   e.JMESPath,
+		XPath:      e.XPath,
+		MaxLatency: config.Duration(e.MaxLatency),
+	}
+}
+
+func configMetadata(comment, title, description string, tags []string, links []Link) config.Metadata {
+	return config.Metadata{
+		Comment:     comment,
+		Title:       title,
+		Description: description,
+		Tags:        tags,
+		Links:       configLinks(links),
 	}
 }
 
 func configLinks(links []Link) []config.Link {
+	if len(links) == 0 {
+		return nil
+	}
 	res := make([]config.Link, len(links))
 	for i, l := range links {
 		res[i] = config.Link{
-			HRef:     l.HRef,
-			HRefLang: l.HRefLang,
-			Audience: l.Audience,
-			Rel:      l.Rel,
-			Title:    l.Title,
+			HRef:       l.HRef,
+			HRefLang:   l.HRefLang,
+			Audience:   l.Audience,
+			Rel:        l.Rel,
+			Title:      l.Title,
+			Type:       l.Type,
+			IsTemplate: l.IsTemplate,
 		}
 	}
 	return res
@@ -144,15 +220,290 @@ func configClient(c Client) *config.Client {
 	case *GRPCClient:
 		return &config.Client{
 			GRPC: &config.GRPCClient{
-				DisableReflection: client.DisableReflection,
-				ProtoSet:          client.ProtoSet,
-				Plaintext:         client.Plaintext,
+				DisableReflection:  client.DisableReflection,
+				ProtoSet:           client.ProtoSet,
+				PreferProtoSet:     client.PreferProtoSet,
+				Plaintext:          client.Plaintext,
+				Authority:          client.Authority,
+				ServerName:         client.ServerName,
+				DialTimeout:        config.Duration(client.DialTimeout),
+				Deadline:           config.Duration(client.Deadline),
+				Keepalive:          config.Duration(client.Keepalive),
+				MaxRecvMessageSize: client.MaxRecvMessageSize,
+				MaxSendMessageSize: client.MaxSendMessageSize,
+				Protocol:           client.Protocol,
+				Encoding:           client.Encoding,
+			},
+		}
+	}
+	return nil
+}
+
+func configAuth(a Auth) *config.Auth {
+	switch auth := a.(type) {
+	case *NoAuth:
+		return &config.Auth{None: true}
+	case *BasicAuth:
+		return &config.Auth{
+			Basic: &config.BasicAuth{
+				User:     auth.User,
+				Password: auth.Password,
+			},
+		}
+	case *BearerAuth:
+		return &config.Auth{
+			Bearer: &config.BearerAuth{
+				Token: auth.Token,
+			},
+		}
+	case *APIKeyAuth:
+		return &config.Auth{
+			APIKey: &config.APIKeyAuth{
+				Key:    auth.Key,
+				Header: auth.Header,
+				Query:  auth.Query,
+			},
+		}
+	case *OAuth2Auth:
+		return &config.Auth{
+			OAuth2: &config.OAuth2Auth{
+				TokenURL:     auth.TokenURL,
+				ClientID:     auth.ClientID,
+				ClientSecret: auth.ClientSecret,
+				Scopes:       auth.Scopes,
+				RefreshToken: auth.RefreshToken,
+			},
+		}
+	case *DigestAuth:
+		return &config.Auth{
+			Digest: &config.DigestAuth{
+				User:     auth.User,
+				Password: auth.Password,
 			},
 		}
+	case *AWSSigV4Auth:
+		return &config.Auth{
+			AWSSigV4: &config.AWSSigV4Auth{
+				Region:          auth.Region,
+				Service:         auth.Service,
+				AccessKeyID:     auth.AccessKeyID,
+				SecretAccessKey: auth.SecretAccessKey,
+				SessionToken:    auth.SessionToken,
+			},
+		}
+	case *SpecAuth:
+		res := &config.Auth{
+			FromSpec: auth.Spec,
+			TTL:      config.Duration(auth.TTL),
+		}
+		if auth.Extract != nil {
+			out := configOutputFilter(auth.Extract)
+			res.Extract = &config.Extract{
+				JMESPath: out.JMESPath,
+				Dig:      out.Dig,
+			}
+		}
+		return res
 	}
 	return nil
 }
 
+func configOutputs(outs []*OutputConfig) []config.Output {
+	if len(outs) == 0 {
+		return nil
+	}
+	res := make([]config.Output, len(outs))
+	for i, o := range outs {
+		res[i] = configOutput(o)
+	}
+	return res
+}
+
+func configOutput(o *OutputConfig) config.Output {
+	res := configOutputFilter(o.Filter)
+	res.Name = o.Name
+	res.Metadata = configMetadata(o.Comment, o.Title, o.Description, nil, o.Links)
+	res.IncludeMetadata = o.IncludeMetadata
+	return res
+}
+
+// configOutputFilter converts the filter to the output which specifies it.
+// Filters which can't be specified in configuration are omitted.
+func configOutputFilter(f OutputFilter) config.Output {
+	switch o := f.(type) {
+	case *TemplateOutput:
+		return config.Output{
+			Template: &config.TemplateOutput{
+				Text: o.Text,
+				File: o.File,
+			},
+		}
+	case *JMESPathOutput:
+		return config.Output{
+			JMESPath: &config.JMESPathOutput{
+				Query: o.Query,
+			},
+		}
+	case *XPathOutput:
+		return config.Output{
+			XPath: &config.XPathOutput{
+				Query: o.Query,
+			},
+		}
+	case *DigOutput:
+		return config.Output{
+			Dig: &config.DigOutput{
+				Query: o.Query,
+			},
+		}
+	case *JSONOutput:
+		return config.Output{
+			JSON: &config.JSONOutput{
+				Pretty: o.Pretty,
+			},
+		}
+	case *XMLOutput:
+		return config.Output{
+			XML: &config.XMLOutput{
+				Pretty: o.Pretty,
+			},
+		}
+	case *YAMLOutput:
+		return config.Output{
+			YAML: &config.YAMLOutput{},
+		}
+	}
+	return config.Output{}
+}
+
+func configVarSets(sets []*VarSet) []config.VarSet {
+	if len(sets) == 0 {
+		return nil
+	}
+	res := make([]config.VarSet, len(sets))
+	for i, s := range sets {
+		res[i] = config.VarSet{
+			Name:     s.Name,
+			Metadata: configMetadata(s.Comment, s.Title, s.Description, nil, s.Links),
+			Vars:     s.Vars,
+		}
+	}
+	return res
+}
+
+func configFlows(flows []*Flow) []config.Flow {
+	if len(flows) == 0 {
+		return nil
+	}
+	res := make([]config.Flow, len(flows))
+	for i, f := range flows {
+		res[i] = config.Flow{
+			Name:     f.Name,
+			Metadata: configMetadata(f.Comment, f.Title, f.Description, f.Tags, f.Links),
+			Steps:    configSteps(f.Steps),
+			Vars:     f.Vars,
+		}
+	}
+	return res
+}
+
+func configSteps(steps []*Step) []config.Step {
+	if len(steps) == 0 {
+		return nil
+	}
+	res := make([]config.Step, len(steps))
+	for i, s := range steps {
+		res[i] = config.Step{
+			Name:     s.Name,
+			Metadata: configMetadata(s.Comment, s.Title, s.Description, s.Tags, s.Links),
+			Method:   s.Method,
+			Headers:  s.Headers,
+			Form:     s.Form,
+			Body:     s.Body,
+			RawBody:  s.RawBody,
+			Vars:     s.Vars,
+			Captures: configCaptures(s.Captures),
+			Expect:   configExpect(s.Expect),
+		}
+
+		switch t := s.StepType.(type) {
+		case *SpecStep:
+			res[i].Spec = t.Spec
+		case *URLStep:
+			res[i].URL = t.URL
+		}
+	}
+	return res
+}
+
+func configCaptures(caps []*Capture) []config.Capture {
+	if len(caps) == 0 {
+		return nil
+	}
+	res := make([]config.Capture, len(caps))
+	for i, c := range caps {
+		out := configOutputFilter(c.Filter)
+		res[i] = config.Capture{
+			Name:     c.Name,
+			JMESPath: out.JMESPath,
+			Dig:      out.Dig,
+		}
+	}
+	return res
+}
+
+// HasImplicitEndpoint tests whether the only endpoint of the resource is the
+// GET endpoint which is created when none are specified
+func (r *Resource) HasImplicitEndpoint() bool {
+	return hasImplicitEndpoint(r)
+}
+
+func hasImplicitEndpoint(r *Resource) bool {
+	return len(r.Endpoints) == 1 &&
+		r.Endpoints[0].Method == "GET" &&
+		isEmpty(Endpoint{Method: r.Endpoints[0].Method}, *r.Endpoints[0])
+}
+
+// isImplicitRoot tests whether the root resource of the service is the one
+// that is created when the service is loaded
+func isImplicitRoot(r *Resource) bool {
+	if r == nil {
+		return false
+	}
+	root := Resource{
+		Resources:   r.Resources,
+		Endpoints:   r.Endpoints,
+		URITemplate: r.URITemplate,
+	}
+	return (r.URITemplate == nil || r.URITemplate.String() == "") &&
+		(len(r.Endpoints) == 0 || hasImplicitEndpoint(r)) &&
+		isEmpty(root, *r)
+}
+
+// isEmpty tests whether each field of the value either equals the
+// corresponding field of the expected value or is empty
+func isEmpty[T any](expected, v T) bool {
+	ev := reflect.ValueOf(expected)
+	vv := reflect.ValueOf(v)
+	for i := range vv.NumField() {
+		f := vv.Field(i)
+		if !f.CanInterface() || reflect.DeepEqual(f.Interface(), ev.Field(i).Interface()) {
+			continue
+		}
+		switch f.Kind() {
+		case reflect.Slice, reflect.Map:
+			if f.Len() == 0 {
+				continue
+			}
+		}
+		if f.IsZero() {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
 func singleton[T any](t *T) []T {
 	if t == nil {
 		return nil
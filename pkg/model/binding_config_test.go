@@ -5,11 +5,16 @@
 package model_test
 
 import (
+	"os"
+	"testing/fstest"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/Carbonfrost/pastiche/pkg/config"
 	"github.com/Carbonfrost/pastiche/pkg/model"
+	. "github.com/onsi/gomega/gstruct"
+	"sigs.k8s.io/yaml"
 )
 
 var _ = Describe("ToConfig", func() {
@@ -22,4 +27,77 @@ var _ = Describe("ToConfig", func() {
 		}).NotTo(Panic())
 
 	})
+
+	It("converts custom methods", func() {
+		subject := model.New(&config.File{
+			Services: []config.Service{
+				{
+					Name: "dav",
+					Resources: []config.Resource{
+						{
+							Name: "files",
+							Get:  &config.Endpoint{},
+							Methods: config.Methods{
+								"propfind": {Name: "list"},
+								"QUERY":    {},
+							},
+						},
+					},
+				},
+			},
+		})
+
+		var file *config.File
+		Expect(func() {
+			file = model.ToConfig(subject)
+		}).NotTo(Panic())
+
+		res := file.Services[0].Resources[0]
+		Expect(res.Get).NotTo(BeNil())
+		Expect(res.Methods).To(HaveKeyWithValue("PROPFIND", PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("list"),
+		}))))
+		Expect(res.Methods).To(HaveKey("QUERY"))
+	})
+
+	Describe("round trip", func() {
+
+		var roundTrip = func(files ...*config.File) {
+			GinkgoHelper()
+			expected := model.New(files...)
+
+			data, err := yaml.Marshal(model.ToConfig(expected))
+			Expect(err).NotTo(HaveOccurred())
+
+			file, err := config.LoadFile(fstest.MapFS{
+				"pastiche.yml": {Data: data},
+			}, "pastiche.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(model.New(file)).To(Equal(expected))
+		}
+
+		It("is lossless", func() {
+			file, err := config.LoadFile(os.DirFS("testdata"), "round_trip.yml")
+			Expect(err).NotTo(HaveOccurred())
+			roundTrip(file)
+		})
+
+		It("is lossless for builtin files", func() {
+			roundTrip(config.BuiltinFiles()...)
+		})
+
+		It("omits the implicit GET endpoint", func() {
+			subject := model.New(&config.File{
+				Services: []config.Service{
+					{
+						Name:      "s",
+						Resources: []config.Resource{{Name: "r", URI: "/r"}},
+					},
+				},
+			})
+
+			file := model.ToConfig(subject)
+			Expect(file.Services[0].Resources).To(Equal([]config.Resource{{Name: "r", URI: "/r"}}))
+		})
+	})
 })
@@ -357,3 +357,32 @@ var _ = Describe("New", func() {
 		),
 	)
 })
+
+var _ = Describe("Methods", func() {
+
+	It("resolves endpoints for custom methods", func() {
+		subject := model.New(&config.File{
+			Services: []config.Service{
+				{
+					Name: "dav",
+					Resources: []config.Resource{
+						{
+							Name: "files",
+							URI:  "/files",
+							Methods: config.Methods{
+								"propfind": {Headers: config.Header{"Depth": {"1"}}},
+								"MKCOL":    {},
+							},
+						},
+					},
+				},
+			},
+		})
+
+		merged, err := subject.Resolve([]string{"dav", "files"}, "", "PROPFIND")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged.Endpoint().Method).To(Equal("PROPFIND"))
+		Expect(merged.Endpoint().Headers).To(HaveKeyWithValue("Depth", []string{"1"}))
+		Expect(merged.Resource().Endpoints).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,361 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CurlCommand represents a parsed curl command line
+type CurlCommand struct {
+	URL        string
+	Method     string
+	Headers    http.Header
+	Data       []string
+	DataFile   string
+	User       string
+	Get        bool
+	Compressed bool
+}
+
+// curlOptionsWithArg lists the options that take an argument but which
+// have no effect on the endpoint
+var curlOptionsWithArg = map[string]bool{
+	"-o":                true,
+	"--output":          true,
+	"-m":                true,
+	"--max-time":        true,
+	"--connect-timeout": true,
+	"-w":                true,
+	"--write-out":       true,
+	"-x":                true,
+	"--proxy":           true,
+	"--retry":           true,
+	"--cacert":          true,
+	"-E":                true,
+	"--cert":            true,
+	"--key":             true,
+	"--resolve":         true,
+	"-c":                true,
+	"--cookie-jar":      true,
+	"-D":                true,
+	"--dump-header":     true,
+	"--max-redirs":      true,
+	"-r":                true,
+	"--range":           true,
+}
+
+// ParseCurlCommand parses a curl command line as copied from a browser or
+// documentation. Quoting and line continuations are supported.
+func ParseCurlCommand(s string) (*CurlCommand, error) {
+	args, err := splitShellWords(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, errors.New("expression is not a curl command")
+	}
+
+	cmd := &CurlCommand{
+		Headers: http.Header{},
+	}
+	args = args[1:]
+	for len(args) > 0 {
+		arg := args[0]
+		args = args[1:]
+
+		name, value, hasValue := splitCurlOption(arg)
+		takeValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if len(args) == 0 {
+				return "", fmt.Errorf("option %s requires an argument", name)
+			}
+			v := args[0]
+			args = args[1:]
+			return v, nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			cmd.Method, err = takeValue()
+		case "-H", "--header":
+			var h string
+			h, err = takeValue()
+			if k, v, ok := strings.Cut(h, ":"); ok {
+				cmd.Headers.Add(strings.TrimSpace(k), strings.TrimSpace(v))
+			}
+		case "-d", "--data", "--data-binary", "--data-ascii":
+			var d string
+			d, err = takeValue()
+			if file, ok := strings.CutPrefix(d, "@"); ok {
+				cmd.DataFile = file
+			} else {
+				cmd.Data = append(cmd.Data, d)
+			}
+		case "--data-raw":
+			var d string
+			d, err = takeValue()
+			cmd.Data = append(cmd.Data, d)
+		case "--data-urlencode":
+			var d string
+			d, err = takeValue()
+			if k, v, ok := strings.Cut(d, "="); ok {
+				d = k + "=" + url.QueryEscape(v)
+			} else {
+				d = url.QueryEscape(d)
+			}
+			cmd.Data = append(cmd.Data, d)
+		case "-F", "--form", "--form-string":
+			err = fmt.Errorf("option %s is not supported because multipart form data cannot be imported", name)
+		case "-u", "--user":
+			cmd.User, err = takeValue()
+		case "-A", "--user-agent":
+			var v string
+			v, err = takeValue()
+			cmd.Headers.Set("User-Agent", v)
+		case "-e", "--referer":
+			var v string
+			v, err = takeValue()
+			cmd.Headers.Set("Referer", v)
+		case "-b", "--cookie":
+			var v string
+			v, err = takeValue()
+			cmd.Headers.Add("Cookie", v)
+		case "--url":
+			cmd.URL, err = takeValue()
+		case "-I", "--head":
+			cmd.Method = "HEAD"
+		case "-G", "--get":
+			cmd.Get = true
+		case "--compressed":
+			cmd.Compressed = true
+		default:
+			if curlOptionsWithArg[name] {
+				_, err = takeValue()
+			} else if !strings.HasPrefix(arg, "-") {
+				cmd.URL = arg
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.URL == "" {
+		return nil, errors.New("curl command must specify a URL")
+	}
+	if cmd.DataFile != "" && (len(cmd.Data) > 0 || cmd.Get) {
+		return nil, errors.New("data read from a file cannot be combined with other data or -G")
+	}
+	if cmd.Get && len(cmd.Data) > 0 {
+		sep := "?"
+		if strings.Contains(cmd.URL, "?") {
+			sep = "&"
+		}
+		cmd.URL += sep + strings.Join(cmd.Data, "&")
+		cmd.Data = nil
+	}
+	if cmd.Method == "" {
+		cmd.Method = "GET"
+		if len(cmd.Data) > 0 || cmd.DataFile != "" {
+			cmd.Method = "POST"
+		}
+	}
+	return cmd, nil
+}
+
+// ToEndpoint converts the command to an endpoint. JSON data becomes the
+// body; URL-encoded data becomes the form. Data read from a file becomes
+// the body file.
+func (c *CurlCommand) ToEndpoint() *Endpoint {
+	ep := &Endpoint{
+		Method:  strings.ToUpper(c.Method),
+		Headers: map[string][]string(c.Headers.Clone()),
+	}
+	if len(ep.Headers) == 0 {
+		ep.Headers = nil
+	}
+
+	if len(c.Data) > 0 {
+		data := strings.Join(c.Data, "&")
+		contentType := c.Headers.Get("Content-Type")
+
+		var decoded any
+		if err := json.Unmarshal([]byte(data), &decoded); err == nil {
+			ep.Body = decoded
+		} else if form, err := url.ParseQuery(data); err == nil && isFormContentType(contentType) {
+			ep.Form = form
+		} else {
+			ep.RawBody = data
+		}
+	}
+	ep.BodyFile = c.DataFile
+
+	if c.User != "" {
+		user, password, _ := strings.Cut(c.User, ":")
+		ep.Auth = &BasicAuth{User: user, Password: password}
+	}
+	return ep
+}
+
+// Server gets the server which is implied by the URL of the command
+func (c *CurlCommand) Server() *Server {
+	return serverForURL(c.URL)
+}
+
+// Server gets the server which is implied by the URL of the call
+func (f FetchCall) Server() *Server {
+	return serverForURL(f.URL)
+}
+
+func serverForURL(s string) *Server {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return &Server{
+		BaseURL: (&url.URL{Scheme: u.Scheme, Host: u.Host}).String(),
+	}
+}
+
+func isFormContentType(s string) bool {
+	return s == "" || strings.HasPrefix(s, "application/x-www-form-urlencoded")
+}
+
+// splitCurlOption splits an option from its value when they are combined,
+// as in -XPOST or --request=POST
+func splitCurlOption(arg string) (string, string, bool) {
+	if strings.HasPrefix(arg, "--") {
+		name, value, ok := strings.Cut(arg, "=")
+		return name, value, ok
+	}
+	if strings.HasPrefix(arg, "-") && len(arg) > 2 {
+		name := arg[:2]
+		if takesCurlValue(name) {
+			return name, arg[2:], true
+		}
+		// Combined flags like -sSL don't have values. The last one is
+		// retained in case it is meaningful, such as -sI
+		return "-" + arg[len(arg)-1:], "", false
+	}
+	return arg, "", false
+}
+
+func takesCurlValue(name string) bool {
+	switch name {
+	case "-X", "-H", "-d", "-F", "-u", "-A", "-e", "-b":
+		return true
+	}
+	return curlOptionsWithArg[name]
+}
+
+// splitShellWords splits the command line into words using the quoting
+// rules of POSIX shells, including ANSI-C quoting ($'...') which browsers
+// use when copying requests
+func splitShellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+	)
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] != '\n' && s[i] != '\r' {
+					current.WriteByte(s[i])
+					inWord = true
+				}
+			}
+
+		case ch == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			current.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+
+		case ch == '$' && i+1 < len(s) && s[i+1] == '\'':
+			n, err := readANSIQuoted(s[i+2:], &current)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 1
+			inWord = true
+
+		case ch == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inWord = true
+
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+
+		default:
+			current.WriteByte(ch)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// readANSIQuoted reads the contents of $'...' up to and including the
+// closing quote, returning the number of bytes consumed
+func readANSIQuoted(s string, w *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			return i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				break
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				w.WriteByte('\n')
+			case 't':
+				w.WriteByte('\t')
+			case 'r':
+				w.WriteByte('\r')
+			default:
+				w.WriteByte(s[i])
+			}
+		default:
+			w.WriteByte(s[i])
+		}
+	}
+	return 0, errors.New("unterminated single quote")
+}
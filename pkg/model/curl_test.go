@@ -0,0 +1,166 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"net/http"
+
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("ParseCurlCommand", func() {
+
+	DescribeTable("examples", func(command string, expected OmegaMatcher) {
+		cmd, err := model.ParseCurlCommand(command)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd).To(PointTo(expected))
+	},
+		Entry("nominal",
+			`curl https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"URL":    Equal("https://api.example.com/users"),
+				"Method": Equal("GET"),
+			}),
+		),
+		Entry("method and headers",
+			`curl -X PUT -H 'Accept: application/json' --header="X-Trace: 1" https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"Method": Equal("PUT"),
+				"Headers": Equal(http.Header{
+					"Accept":  {"application/json"},
+					"X-Trace": {"1"},
+				}),
+			}),
+		),
+		Entry("combined option and value",
+			`curl -XDELETE https://api.example.com/users/1`,
+			MatchFields(IgnoreExtras, Fields{
+				"Method": Equal("DELETE"),
+			}),
+		),
+		Entry("data implies POST",
+			`curl https://api.example.com/users --data-raw '{"name":"a"}'`,
+			MatchFields(IgnoreExtras, Fields{
+				"Method": Equal("POST"),
+				"Data":   Equal([]string{`{"name":"a"}`}),
+			}),
+		),
+		Entry("data URL encoding",
+			`curl https://api.example.com/users --data-urlencode 'q=a b'`,
+			MatchFields(IgnoreExtras, Fields{
+				"Data": Equal([]string{"q=a+b"}),
+			}),
+		),
+		Entry("get data",
+			`curl -G https://api.example.com/users?a=1 -d b=2`,
+			MatchFields(IgnoreExtras, Fields{
+				"Method": Equal("GET"),
+				"URL":    Equal("https://api.example.com/users?a=1&b=2"),
+				"Data":   BeNil(),
+			}),
+		),
+		Entry("data file",
+			`curl --data-binary @body.json https://api.example.com/upload`,
+			MatchFields(IgnoreExtras, Fields{
+				"Method":   Equal("POST"),
+				"Data":     BeNil(),
+				"DataFile": Equal("body.json"),
+			}),
+		),
+		Entry("raw data is not a file",
+			`curl --data-raw @body https://api.example.com/upload`,
+			MatchFields(IgnoreExtras, Fields{
+				"Data":     Equal([]string{"@body"}),
+				"DataFile": BeEmpty(),
+			}),
+		),
+		Entry("user and compressed",
+			`curl -u me:secret --compressed -sSL https://api.example.com/`,
+			MatchFields(IgnoreExtras, Fields{
+				"User":       Equal("me:secret"),
+				"Compressed": BeTrue(),
+			}),
+		),
+		Entry("ignored options with arguments",
+			`curl -o out.json --max-time 5 https://api.example.com/`,
+			MatchFields(IgnoreExtras, Fields{
+				"URL": Equal("https://api.example.com/"),
+			}),
+		),
+		Entry("line continuations and ANSI-C quoting",
+			"curl 'https://api.example.com/' \\\n  -H $'X-Quote: it\\'s' \\\n  -H \"X-Escaped: \\\"a\\\"\"",
+			MatchFields(IgnoreExtras, Fields{
+				"URL": Equal("https://api.example.com/"),
+				"Headers": Equal(http.Header{
+					"X-Quote":   {"it's"},
+					"X-Escaped": {`"a"`},
+				}),
+			}),
+		),
+	)
+
+	DescribeTable("errors", func(command string, expected OmegaMatcher) {
+		_, err := model.ParseCurlCommand(command)
+		Expect(err).To(expected)
+	},
+		Entry("empty string", "", MatchError("expression is not a curl command")),
+		Entry("missing URL", "curl -X GET", MatchError("curl command must specify a URL")),
+		Entry("missing argument", "curl https://example.com -H", MatchError("option -H requires an argument")),
+		Entry("unterminated quote", "curl 'https://example.com", MatchError("unterminated single quote")),
+		Entry("multipart form", "curl -F name=a https://example.com", MatchError(
+			"option -F is not supported because multipart form data cannot be imported",
+		)),
+		Entry("data file with data", "curl -d a=1 -d @body https://example.com", MatchError(
+			"data read from a file cannot be combined with other data or -G",
+		)),
+	)
+
+	DescribeTable("ToEndpoint", func(command string, expected OmegaMatcher) {
+		cmd, err := model.ParseCurlCommand(command)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cmd.ToEndpoint()).To(PointTo(expected))
+	},
+		Entry("JSON body",
+			`curl -H 'Content-Type: application/json' -d '{"name":"a"}' https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"Method": Equal("POST"),
+				"Body":   Equal(map[string]any{"name": "a"}),
+			}),
+		),
+		Entry("URL-encoded body",
+			`curl -d a=1 -d b=2 https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"Form": Equal(map[string][]string{"a": {"1"}, "b": {"2"}}),
+			}),
+		),
+		Entry("raw body",
+			`curl -H 'Content-Type: text/plain' -d 'hello' https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"RawBody": Equal("hello"),
+			}),
+		),
+		Entry("data file",
+			`curl -d @- https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"BodyFile": Equal("-"),
+				"RawBody":  BeNil(),
+			}),
+		),
+		Entry("basic auth",
+			`curl -u me:secret https://api.example.com/users`,
+			MatchFields(IgnoreExtras, Fields{
+				"Auth": Equal(&model.BasicAuth{User: "me", Password: "secret"}),
+			}),
+		),
+	)
+
+	It("provides the server", func() {
+		cmd, _ := model.ParseCurlCommand(`curl https://api.example.com:8443/users?a=1`)
+		Expect(cmd.Server()).To(Equal(&model.Server{BaseURL: "https://api.example.com:8443"}))
+	})
+})
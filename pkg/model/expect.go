@@ -0,0 +1,136 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Set parses an expectation in one of the forms status=CODE[,CODE...],
+// header=NAME:PATTERN, jmespath=QUERY, xpath=QUERY, or latency=DURATION.
+// The expectation is added to any that were set previously.
+func (e *Expectation) Set(arg string) error {
+	kind, value, ok := strings.Cut(arg, "=")
+	if !ok {
+		return fmt.Errorf("invalid expectation %q: expected KIND=VALUE", arg)
+	}
+
+	switch kind {
+	case "status":
+		for code := range strings.SplitSeq(value, ",") {
+			c, err := strconv.Atoi(strings.TrimSpace(code))
+			if err != nil {
+				return fmt.Errorf("invalid status code %q", code)
+			}
+			e.Status = append(e.Status, c)
+		}
+
+	case "header":
+		name, pattern, ok := strings.Cut(value, ":")
+		if !ok {
+			return fmt.Errorf("invalid header expectation %q: expected NAME:PATTERN", value)
+		}
+		if e.Headers == nil {
+			e.Headers = map[string][]string{}
+		}
+		name = strings.TrimSpace(name)
+		e.Headers[name] = append(e.Headers[name], strings.TrimSpace(pattern))
+
+	case "jmespath":
+		e.JMESPath = append(e.JMESPath, value)
+
+	case "xpath":
+		e.XPath = append(e.XPath, value)
+
+	case "latency":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		e.MaxLatency = d
+
+	default:
+		return fmt.Errorf("unknown expectation %q", kind)
+	}
+	return nil
+}
+
+// Reset clears the expectation so that each occurrence of the flag
+// provides its own expectation
+func (e *Expectation) Reset() {
+	*e = Expectation{}
+}
+
+// Copy creates a copy of the expectation
+func (e *Expectation) Copy() *Expectation {
+	res := *e
+	return &res
+}
+
+func (e *Expectation) String() string {
+	if e == nil {
+		return ""
+	}
+
+	var items []string
+	if len(e.Status) > 0 {
+		codes := make([]string, len(e.Status))
+		for i, c := range e.Status {
+			codes[i] = strconv.Itoa(c)
+		}
+		items = append(items, "status="+strings.Join(codes, ","))
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Headers)) {
+		for _, p := range e.Headers[name] {
+			items = append(items, "header="+name+":"+p)
+		}
+	}
+	for _, q := range e.JMESPath {
+		items = append(items, "jmespath="+q)
+	}
+	for _, q := range e.XPath {
+		items = append(items, "xpath="+q)
+	}
+	if e.MaxLatency > 0 {
+		items = append(items, "latency="+e.MaxLatency.String())
+	}
+	return strings.Join(items, " ")
+}
+
+func mergeExpectations(x, y *Expectation) *Expectation {
+	if x == nil {
+		return y
+	}
+	if y == nil {
+		return x
+	}
+
+	headers := maps.Clone(x.Headers)
+	for k, v := range y.Headers {
+		if headers == nil {
+			headers = map[string][]string{}
+		}
+		headers[k] = append(slices.Clone(headers[k]), v...)
+	}
+
+	status := x.Status
+	if len(y.Status) > 0 {
+		status = y.Status
+	}
+
+	return &Expectation{
+		Status:     status,
+		Headers:    headers,
+		JMESPath:   slices.Concat(x.JMESPath, y.JMESPath),
+		XPath:      slices.Concat(x.XPath, y.XPath),
+		MaxLatency: cmp.Or(y.MaxLatency, x.MaxLatency),
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("Expectation", func() {
+
+	Describe("Set", func() {
+
+		DescribeTable("examples", func(args []string, expected *model.Expectation) {
+			actual := new(model.Expectation)
+			for _, arg := range args {
+				Expect(actual.Set(arg)).To(Succeed())
+			}
+			Expect(actual).To(Equal(expected))
+		},
+			Entry("status", []string{"status=200"}, &model.Expectation{Status: []int{200}}),
+			Entry("status list", []string{"status=200, 204", "status=201"}, &model.Expectation{Status: []int{200, 204, 201}}),
+			Entry("header", []string{"header=Content-Type: ^text/"}, &model.Expectation{
+				Headers: map[string][]string{"Content-Type": {"^text/"}},
+			}),
+			Entry("jmespath", []string{"jmespath=a == `1`"}, &model.Expectation{JMESPath: []string{"a == `1`"}}),
+			Entry("xpath", []string{"xpath=//a"}, &model.Expectation{XPath: []string{"//a"}}),
+			Entry("latency", []string{"latency=1.5s"}, &model.Expectation{MaxLatency: 1500 * time.Millisecond}),
+		)
+
+		DescribeTable("errors", func(arg string, expected types.GomegaMatcher) {
+			err := new(model.Expectation).Set(arg)
+			Expect(err).To(expected)
+		},
+			Entry("missing kind", "200", MatchError(`invalid expectation "200": expected KIND=VALUE`)),
+			Entry("unknown kind", "size=2", MatchError(`unknown expectation "size"`)),
+			Entry("bad status", "status=ok", MatchError(`invalid status code "ok"`)),
+			Entry("bad header", "header=Accept", MatchError(`invalid header expectation "Accept": expected NAME:PATTERN`)),
+			Entry("bad latency", "latency=soon", MatchError(ContainSubstring(`invalid duration "soon"`))),
+		)
+	})
+
+	It("formats as a string", func() {
+		e := &model.Expectation{
+			Status:     []int{200, 201},
+			Headers:    map[string][]string{"Accept": {"json"}},
+			MaxLatency: time.Second,
+		}
+		Expect(e.String()).To(Equal("status=200,201 header=Accept:json latency=1s"))
+	})
+})
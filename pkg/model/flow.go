@@ -0,0 +1,91 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/Carbonfrost/joe-cli-http/uritemplates"
+)
+
+// Flow gets the flow with the given name
+func (m *Model) Flow(name string) (*Flow, bool) {
+	for _, f := range m.Flows {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// ResolveStep resolves the resource targeted by a step in the flow. The
+// method, headers, body, and vars of the step are applied over the endpoint
+// which was resolved.
+func (m *Model) ResolveStep(f *Flow, s *Step, server string) (ResolvedResource, error) {
+	var resolved *resolvedResource
+
+	switch t := s.StepType.(type) {
+	case *SpecStep:
+		spec, err := t.ServiceSpec()
+		if err != nil {
+			return nil, err
+		}
+		rr, err := m.Resolve(spec, server, s.Method)
+		if err != nil {
+			return nil, err
+		}
+		resolved = rr.(*resolvedResource)
+
+	case *URLStep:
+		uri, err := uritemplates.Parse(t.URL)
+		if err != nil {
+			return nil, err
+		}
+		res := &Resource{
+			URITemplate: uri,
+			Endpoints: []*Endpoint{
+				{Method: cmp.Or(s.Method, "GET")},
+			},
+		}
+		resolved = &resolvedResource{
+			service:  &Service{Resource: res},
+			lineage:  []*Resource{res},
+			endpoint: res.Endpoints[0],
+		}
+
+	default:
+		return nil, fmt.Errorf("step %q must specify either spec or url", s.Name)
+	}
+
+	return &resolvedResource{
+		service:  resolved.service,
+		lineage:  resolved.lineage,
+		server:   resolved.server,
+		endpoint: stepEndpoint(resolved.endpoint, f, s),
+	}, nil
+}
+
+// ServiceSpec parses the service spec which the step targets
+func (s *SpecStep) ServiceSpec() (ServiceSpec, error) {
+	return ParseServiceSpec(s.Spec)
+}
+
+func stepEndpoint(ep *Endpoint, f *Flow, s *Step) *Endpoint {
+	res := *ep
+	res.Method = cmp.Or(s.Method, ep.Method)
+	res.Headers = ReduceHeader(ReduceHeader(map[string][]string{}, ep.Headers), s.Headers)
+	res.Vars = reduceVars(reduceVars(reduceVars(map[string]any{}, ep.Vars), f.Vars), s.Vars)
+
+	// Any content on the step replaces content from the endpoint
+	if s.Form != nil || s.Body != nil || s.RawBody != nil {
+		res.Form = s.Form
+		res.Body = s.Body
+		res.RawBody = s.RawBody
+		res.BodyFile = ""
+	}
+	res.Expect = mergeExpectations(ep.Expect, s.Expect)
+	return &res
+}
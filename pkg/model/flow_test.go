@@ -0,0 +1,160 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("ResolveStep", func() {
+
+	var subject = func() *model.Model {
+		return model.New(
+			&config.File{
+				Services: []config.Service{
+					{
+						Name: "a",
+						Servers: []config.Server{
+							{Name: "default", BaseURL: "https://example.com"},
+						},
+						Resources: []config.Resource{
+							{
+								Name: "items",
+								URI:  "items/{id}",
+								Get: &config.Endpoint{
+									Headers: config.Header{"Accept": {"application/json"}},
+									Vars:    map[string]any{"id": "endpoint"},
+									Expect: &config.Expect{
+										Status:   []int{200},
+										JMESPath: []string{"id"},
+									},
+								},
+								Post: &config.Endpoint{
+									BodyFile: "testdata/missing.json",
+								},
+							},
+						},
+					},
+				},
+				Flows: []config.Flow{
+					{
+						Name: "f",
+						Vars: map[string]any{"id": "flow"},
+						Steps: []config.Step{
+							{
+								Name: "spec",
+								Spec: "a.items",
+								Expect: &config.Expect{
+									Status:   []int{201},
+									JMESPath: []string{"name"},
+								},
+							},
+							{
+								Name:    "override",
+								Spec:    "a.items",
+								Method:  "POST",
+								Headers: config.Header{"+Accept": {"text/plain"}},
+								Body:    map[string]any{"name": "step"},
+								Vars:    map[string]any{"id": "step"},
+							},
+							{
+								Name:   "url",
+								URL:    "https://other.example/{id}",
+								Method: "DELETE",
+							},
+							{
+								Name: "missing",
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+
+	DescribeTable("examples", func(index int, expectedURL string, expectedHeaders types.GomegaMatcher) {
+		mo := subject()
+		flow, ok := mo.Flow("f")
+		Expect(ok).To(BeTrue())
+
+		rr, err := mo.ResolveStep(flow, flow.Steps[index], "")
+		Expect(err).NotTo(HaveOccurred())
+
+		req, err := rr.EvalRequest(nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(req.URL.String()).To(Equal(expectedURL))
+		Expect(req.Headers).To(expectedHeaders)
+	},
+		Entry("flow vars apply to endpoint", 0,
+			"https://example.com/items/flow",
+			HaveKeyWithValue("Accept", []string{"application/json"}),
+		),
+		Entry("step overrides", 1,
+			"https://example.com/items/step",
+			HaveKeyWithValue("Accept", []string{"text/plain"}),
+		),
+		Entry("URL step", 2,
+			"https://other.example/flow",
+			BeEmpty(),
+		),
+	)
+
+	It("applies the step method and body", func() {
+		mo := subject()
+		flow, _ := mo.Flow("f")
+
+		rr, _ := mo.ResolveStep(flow, flow.Steps[1], "")
+		Expect(rr.Endpoint().Method).To(Equal("POST"))
+		Expect(rr.Endpoint().Body).To(Equal(map[string]any{"name": "step"}))
+	})
+
+	It("sends the step body rather than the endpoint body file", func() {
+		mo := subject()
+		flow, _ := mo.Flow("f")
+
+		rr, _ := mo.ResolveStep(flow, flow.Steps[1], "")
+		req, err := rr.EvalRequest(nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := io.ReadAll(req.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(MatchJSON(`{"name": "step"}`))
+	})
+
+	It("merges the step expectation with the endpoint", func() {
+		mo := subject()
+		flow, _ := mo.Flow("f")
+
+		rr, _ := mo.ResolveStep(flow, flow.Steps[0], "")
+		Expect(rr.Endpoint().Expect).To(Equal(&model.Expectation{
+			Status:   []int{201},
+			JMESPath: []string{"id", "name"},
+		}))
+	})
+
+	It("defaults URL steps to GET", func() {
+		mo := subject()
+		flow, _ := mo.Flow("f")
+		flow.Steps[2].Method = ""
+
+		rr, _ := mo.ResolveStep(flow, flow.Steps[2], "")
+		Expect(rr.Endpoint().Method).To(Equal("GET"))
+	})
+
+	It("returns an error when the step has no target", func() {
+		mo := subject()
+		flow, _ := mo.Flow("f")
+
+		_, err := mo.ResolveStep(flow, flow.Steps[3], "")
+		Expect(err).To(MatchError(`step "missing" must specify either spec or url`))
+	})
+})
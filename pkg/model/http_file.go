@@ -0,0 +1,219 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/internal/log"
+)
+
+var (
+	pasticheExpr = regexp.MustCompile(`\$\{([^{}]+)\}`)
+)
+
+// httpFileBaseURL is the name of the file variable which provides the base
+// URL of the server
+const httpFileBaseURL = "baseUrl"
+
+// WriteHTTPFile writes the endpoints of the service as an .http file as
+// used by the request runners of editors. The vars of the service and its
+// first server become file variables, and the base URL of the server is
+// provided by the baseUrl variable. Each request contains the headers,
+// query, auth, and body which it inherits.
+func WriteHTTPFile(w io.Writer, svc *Service) error {
+	out := bufio.NewWriter(w)
+
+	var server *Server
+	vars := maps.Clone(svc.Vars)
+	if len(svc.Servers) > 0 {
+		server = svc.Servers[0]
+		if vars == nil {
+			vars = map[string]any{}
+		}
+		maps.Copy(vars, server.Vars)
+		vars[httpFileBaseURL] = httpFileTemplate(server.BaseURL)
+	}
+	for _, k := range slices.Sorted(maps.Keys(vars)) {
+		fmt.Fprintf(out, "@%s = %s\n", k, httpFileValue(fmt.Sprint(vars[k])))
+	}
+
+	first := len(vars) == 0
+	var walk func(r *Resource, lineage []*Resource)
+	walk = func(r *Resource, lineage []*Resource) {
+		lineage = append(lineage, r)
+		if !r.HasImplicitEndpoint() {
+			for _, ep := range r.Endpoints {
+				if !first {
+					fmt.Fprintln(out)
+				}
+				first = false
+				writeHTTPFileRequest(out, &resolvedResource{
+					service:  svc,
+					lineage:  lineage,
+					endpoint: ep,
+					server:   server,
+				})
+			}
+		}
+		for _, child := range r.Resources {
+			walk(child, lineage)
+		}
+	}
+	if svc.Resource != nil {
+		walk(svc.Resource, nil)
+	}
+	return out.Flush()
+}
+
+func writeHTTPFileRequest(w io.Writer, r *resolvedResource) {
+	ep := r.Endpoint()
+	fmt.Fprintln(w, strings.TrimSpace("### "+cmp.Or(ep.Title, r.Resource().Title)))
+	if ep.Name != "" {
+		fmt.Fprintf(w, "# @name %s\n", ep.Name)
+	}
+
+	headers := resolveHeaders(r)
+	query := resolveQuery(r)
+	switch auth := resolveAuth(r).(type) {
+	case *BasicAuth:
+		headers.Set("Authorization", "Basic "+auth.User+":"+auth.Password)
+	case *BearerAuth:
+		headers.Set("Authorization", "Bearer "+auth.Token)
+	case *APIKeyAuth:
+		if auth.Query != "" {
+			query.Set(auth.Query, auth.Key)
+		} else {
+			headers.Set(auth.HeaderName(), auth.Key)
+		}
+	case *DigestAuth, *OAuth2Auth, *AWSSigV4Auth, *SpecAuth:
+		log.Warnf("warning: omitted the auth of %s %s because .http files do not support it", strings.ToUpper(cmp.Or(ep.Method, "GET")), httpFileURL(r, query))
+	}
+
+	var (
+		body string
+		form url.Values
+	)
+	switch {
+	case ep.Form != nil:
+		form = ep.Form
+	case ep.Body != nil:
+		body = httpFileJSON(ep.Body)
+	case ep.RawBody != nil:
+		body = string(bodyToBytes(ep.RawBody))
+	case r.Resource().Form != nil:
+		form = r.Resource().Form
+	case r.Resource().Body != nil:
+		body = httpFileJSON(r.Resource().Body)
+	case r.Resource().RawBody != nil:
+		body = string(bodyToBytes(r.Resource().RawBody))
+	}
+	if form != nil {
+		body = httpFileQuery(form)
+		if headers.Get("Content-Type") == "" {
+			headers.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+
+	fmt.Fprintf(w, "%s %s\n", strings.ToUpper(cmp.Or(ep.Method, "GET")), httpFileURL(r, query))
+	for _, k := range slices.Sorted(maps.Keys(headers)) {
+		for _, v := range headers[k] {
+			fmt.Fprintf(w, "%s: %s\n", k, httpFileValue(v))
+		}
+	}
+	if body != "" {
+		fmt.Fprintf(w, "\n%s\n", httpFileValue(body))
+	}
+}
+
+// httpFileURL gets the URL of the request, which is relative to the baseUrl
+// variable unless it is absolute. Template expressions which expand into
+// the query string are moved to the end of the URL.
+func httpFileURL(r *resolvedResource, query url.Values) string {
+	prefix := make([]string, 0, len(r.Lineage()))
+	for _, c := range r.Lineage() {
+		if c.URITemplate != nil {
+			prefix = append(prefix, c.URITemplate.String())
+		}
+	}
+
+	var base string
+	if r.Server() != nil {
+		base = "{{" + httpFileBaseURL + "}}"
+	}
+	if len(prefix) > 0 && looksLikeURLPattern.MatchString(prefix[0]) {
+		base = httpFileTemplate(strings.TrimSuffix(prefix[0], "/"))
+		prefix = prefix[1:]
+	}
+
+	var params []string
+	p := ReplaceTemplateExprs(path.Join(prefix...), func(expr, op string, names []string) string {
+		if op != "?" && op != "&" {
+			return expr
+		}
+		for _, v := range names {
+			params = append(params, v+"={{"+v+"}}")
+		}
+		return ""
+	})
+
+	u := base
+	if p != "" {
+		u += "/" + httpFileTemplate(p)
+	}
+	if len(query) > 0 {
+		params = append(params, httpFileQuery(query))
+	}
+	if len(params) > 0 {
+		u += "?" + strings.Join(params, "&")
+	}
+	return u
+}
+
+// httpFileTemplate converts the URI template expressions in s to variables
+func httpFileTemplate(s string) string {
+	return ReplaceTemplateExprs(s, func(_, op string, names []string) string {
+		var prefix string
+		if op == "/" || op == "." {
+			prefix = op
+		}
+		var res []string
+		for _, v := range names {
+			res = append(res, prefix+"{{"+v+"}}")
+		}
+		return strings.Join(res, "")
+	})
+}
+
+// httpFileValue converts the Pastiche expressions in s to variables
+func httpFileValue(s string) string {
+	return pasticheExpr.ReplaceAllString(s, "{{$1}}")
+}
+
+// httpFileQuery encodes the values while leaving expressions unescaped
+func httpFileQuery(values url.Values) string {
+	return strings.NewReplacer("%24", "$", "%7B", "{", "%7D", "}").Replace(values.Encode())
+}
+
+func httpFileJSON(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	"bytes"
+	"testing/fstest"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("WriteHTTPFile", func() {
+
+	var subject = func() string {
+		mo := model.New(&config.File{
+			Service: &config.Service{
+				Name: "example",
+				Servers: []config.Server{
+					{Name: "default", BaseURL: "https://api.example.com"},
+				},
+				Vars: map[string]any{"limit": 10},
+				Auth: &config.Auth{
+					Bearer: &config.BearerAuth{Token: "${token}"},
+				},
+				Resources: []config.Resource{
+					{
+						Name:    "users",
+						URI:     "users{?limit}",
+						Headers: config.Header{"Accept": {"application/json"}},
+						Get: &config.Endpoint{
+							Name: "listUsers",
+							Metadata: config.Metadata{
+								Title: "List users",
+							},
+						},
+						Post: &config.Endpoint{
+							Headers: config.Header{"Content-Type": {"application/json"}},
+							Body:    map[string]any{"name": "${name}"},
+						},
+						Resources: []config.Resource{
+							{
+								Name: "user",
+								URI:  "{id}",
+								Delete: &config.Endpoint{
+									Form: config.Form{"reason": {"${reason}"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		svc, _ := mo.Service("example")
+
+		var buf bytes.Buffer
+		Expect(model.WriteHTTPFile(&buf, svc)).To(Succeed())
+		return buf.String()
+	}
+
+	It("writes the requests of each endpoint", func() {
+		Expect(subject()).To(Equal(`@baseUrl = https://api.example.com
+@limit = 10
+
+### List users
+# @name listUsers
+GET {{baseUrl}}/users?limit={{limit}}
+Accept: application/json
+Authorization: Bearer {{token}}
+
+###
+POST {{baseUrl}}/users?limit={{limit}}
+Accept: application/json
+Authorization: Bearer {{token}}
+Content-Type: application/json
+
+{
+  "name": "{{name}}"
+}
+
+###
+DELETE {{baseUrl}}/users/{{id}}?limit={{limit}}
+Accept: application/json
+Authorization: Bearer {{token}}
+Content-Type: application/x-www-form-urlencoded
+
+reason={{reason}}
+`))
+	})
+
+	It("omits auth which .http files do not support", func() {
+		mo := model.New(&config.File{
+			Service: &config.Service{
+				Name: "example",
+				Servers: []config.Server{
+					{Name: "default", BaseURL: "https://api.example.com"},
+				},
+				Auth: &config.Auth{
+					Digest: &config.DigestAuth{User: "u", Password: "p"},
+				},
+				Resources: []config.Resource{
+					{Name: "users", URI: "users", Get: &config.Endpoint{Name: "listUsers"}},
+				},
+			},
+		})
+		svc, _ := mo.Service("example")
+
+		var buf bytes.Buffer
+		Expect(model.WriteHTTPFile(&buf, svc)).To(Succeed())
+		Expect(buf.String()).To(Equal(`@baseUrl = https://api.example.com
+
+###
+# @name listUsers
+GET {{baseUrl}}/users
+`))
+	})
+
+	It("can be read as configuration", func() {
+		file, err := config.LoadFile(fstest.MapFS{
+			"example.http": {Data: []byte(subject())},
+		}, "example.http")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file.Service.Resources).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"URI": Equal("{+baseUrl}/users"),
+			"Get": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Name":  Equal("listUsers"),
+				"Query": Equal(config.Header{"limit": {"${limit}"}}),
+			})),
+		})))
+	})
+})
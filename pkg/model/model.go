@@ -15,6 +15,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
 	"github.com/Carbonfrost/joe-cli-http/uritemplates"
@@ -102,10 +103,23 @@ type Endpoint struct {
 	Links       []Link
 	Body        any
 	RawBody     any
+	BodyFile    string
 	Vars        map[string]any
 	Auth        Auth
 	Output      []*OutputConfig
 	VarSets     []*VarSet
+	Expect      *Expectation
+}
+
+// Expectation describes assertions made about a response. The status
+// must be one of the given codes; headers and XPath and JMESPath queries
+// must match; and the response must arrive within the max latency.
+type Expectation struct {
+	Status     []int
+	Headers    map[string][]string
+	JMESPath   []string
+	XPath      []string
+	MaxLatency time.Duration
 }
 
 type Link struct {
@@ -162,6 +176,15 @@ type Step struct {
 	RawBody     any
 	Vars        map[string]any
 	StepType    StepType
+	Captures    []*Capture
+	Expect      *Expectation
+}
+
+// Capture extracts a value from the response of a step, which is
+// then made available as a var to subsequent steps
+type Capture struct {
+	Name   string
+	Filter OutputFilter
 }
 
 type StepType interface {
@@ -236,9 +259,19 @@ type Client interface {
 }
 
 type GRPCClient struct {
-	DisableReflection bool
-	ProtoSet          string
-	Plaintext         bool
+	DisableReflection  bool
+	ProtoSet           string
+	PreferProtoSet     bool
+	Plaintext          bool
+	Authority          string
+	ServerName         string
+	DialTimeout        time.Duration
+	Deadline           time.Duration
+	Keepalive          time.Duration
+	MaxRecvMessageSize int
+	MaxSendMessageSize int
+	Protocol           string
+	Encoding           string
 }
 
 type HTTPClient struct {
@@ -253,6 +286,71 @@ type BasicAuth struct {
 	Password string
 }
 
+// BearerAuth provides a token in the Authorization header using the
+// Bearer scheme
+type BearerAuth struct {
+	Token string
+}
+
+// APIKeyAuth provides a key in either the given header or query string
+// parameter. When neither is specified, the header X-API-Key is used.
+type APIKeyAuth struct {
+	Key    string
+	Header string
+	Query  string
+}
+
+const defaultAPIKeyHeader = "X-API-Key"
+
+// OAuth2Auth obtains an access token from the token endpoint, which is
+// then provided using the Bearer scheme. The client credentials grant
+// is used unless a refresh token is specified.
+type OAuth2Auth struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RefreshToken string
+}
+
+// DigestAuth provides credentials using the Digest scheme. The request is
+// sent again with the response to the challenge when the server responds
+// with 401 Unauthorized.
+type DigestAuth struct {
+	User     string
+	Password string
+}
+
+// AWSSigV4Auth signs the request using AWS Signature Version 4. When they
+// are not specified, the region and credentials are obtained from the
+// standard AWS environment variables.
+type AWSSigV4Auth struct {
+	Region          string
+	Service         string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// NoAuth disables the auth which would otherwise be inherited from the
+// resource, server, or service
+type NoAuth struct{}
+
+// SpecAuth obtains a token by running the request for another service spec
+// and extracting the token from its response with a filter. The token is
+// provided using the Bearer scheme. When TTL is set, the token is cached
+// for that duration.
+type SpecAuth struct {
+	Spec    string
+	Extract OutputFilter
+	TTL     time.Duration
+}
+
+// HeaderName gets the name of the header which provides the key
+func (a *APIKeyAuth) HeaderName() string {
+	return cmp.Or(a.Header, defaultAPIKeyHeader)
+}
+
 // ResolvedResource represents the resource which was selected by its name
 type ResolvedResource interface {
 	Service() *Service
@@ -275,7 +373,10 @@ type resolvedResource struct {
 	service  *Service
 }
 
-var looksLikeURLPattern = regexp.MustCompile(`^(unix|https?)://`)
+var (
+	looksLikeURLPattern = regexp.MustCompile(`^(unix|https?)://`)
+	templateExpr        = regexp.MustCompile(`\{([+#./;?&]?)([^}]*)\}`)
+)
 
 // New creates a new model from configuration files
 func New(files ...*config.File) *Model {
@@ -471,6 +572,37 @@ func resolveURL(base string, prefix []string, vars map[string]any) (*url.URL, er
 	return u.JoinPath(), nil
 }
 
+// ReplaceTemplateExprs replaces each expression in the URI template s with
+// the result of repl, which is passed the expression, its operator, and the
+// names of its variables
+func ReplaceTemplateExprs(s string, repl func(expr, op string, names []string) string) string {
+	return templateExpr.ReplaceAllStringFunc(s, func(expr string) string {
+		m := templateExpr.FindStringSubmatch(expr)
+		return repl(expr, m[1], templateVarNames(m[2]))
+	})
+}
+
+// TemplateVarNames gets the names of the variables in the URI template s
+func TemplateVarNames(s string) []string {
+	var res []string
+	for _, m := range templateExpr.FindAllStringSubmatch(s, -1) {
+		res = append(res, templateVarNames(m[2])...)
+	}
+	return res
+}
+
+func templateVarNames(s string) []string {
+	var res []string
+	for v := range strings.SplitSeq(s, ",") {
+		v = strings.TrimSuffix(v, "*")
+		v, _, _ = strings.Cut(v, ":")
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 func mergeQuery(u *url.URL, newVals url.Values) {
 	if u == nil {
 		return
@@ -515,7 +647,7 @@ func (r *resolvedResource) Output() []*OutputConfig {
 func resolveHeaders(r ResolvedResource) http.Header {
 	return locate(
 		r,
-		reduceHeader,
+		ReduceHeader,
 		http.Header{},
 		func(d *Endpoint) http.Header { return d.Headers },
 		func(r *Resource) http.Header { return r.Headers },
@@ -527,7 +659,7 @@ func resolveHeaders(r ResolvedResource) http.Header {
 func resolveQuery(r ResolvedResource) url.Values {
 	return locate(
 		r,
-		reduceHeader,
+		ReduceHeader,
 		url.Values{},
 		func(d *Endpoint) url.Values { return d.Query },
 		func(r *Resource) url.Values { return r.Query },
@@ -578,7 +710,7 @@ func resolveLinks2(r ResolvedResource) []Link {
 }
 
 func resolveAuth(r ResolvedResource) Auth {
-	return locate(
+	res := locate(
 		r,
 		reduceAuth,
 		nil,
@@ -587,6 +719,10 @@ func resolveAuth(r ResolvedResource) Auth {
 		(*Server).auth,
 		(*Service).auth,
 	)
+	if _, ok := res.(*NoAuth); ok {
+		return nil
+	}
+	return res
 }
 
 func locate[T any](
@@ -692,12 +828,79 @@ func reduceAuth(x, y Auth) Auth {
 				User:     cmp.Or(by.User, bx.User),
 				Password: cmp.Or(by.Password, bx.Password),
 			}
+
+		case *BearerAuth:
+			by := y.(*BearerAuth)
+			return &BearerAuth{
+				Token: cmp.Or(by.Token, bx.Token),
+			}
+
+		case *APIKeyAuth:
+			by := y.(*APIKeyAuth)
+			res := &APIKeyAuth{
+				Key:    cmp.Or(by.Key, bx.Key),
+				Header: bx.Header,
+				Query:  bx.Query,
+			}
+
+			// Location of the key is taken together
+			if by.Header != "" || by.Query != "" {
+				res.Header = by.Header
+				res.Query = by.Query
+			}
+			return res
+
+		case *OAuth2Auth:
+			by := y.(*OAuth2Auth)
+			scopes := bx.Scopes
+			if len(by.Scopes) > 0 {
+				scopes = by.Scopes
+			}
+			return &OAuth2Auth{
+				TokenURL:     cmp.Or(by.TokenURL, bx.TokenURL),
+				ClientID:     cmp.Or(by.ClientID, bx.ClientID),
+				ClientSecret: cmp.Or(by.ClientSecret, bx.ClientSecret),
+				Scopes:       scopes,
+				RefreshToken: cmp.Or(by.RefreshToken, bx.RefreshToken),
+			}
+
+		case *DigestAuth:
+			by := y.(*DigestAuth)
+			return &DigestAuth{
+				User:     cmp.Or(by.User, bx.User),
+				Password: cmp.Or(by.Password, bx.Password),
+			}
+
+		case *AWSSigV4Auth:
+			by := y.(*AWSSigV4Auth)
+			return &AWSSigV4Auth{
+				Region:          cmp.Or(by.Region, bx.Region),
+				Service:         cmp.Or(by.Service, bx.Service),
+				AccessKeyID:     cmp.Or(by.AccessKeyID, bx.AccessKeyID),
+				SecretAccessKey: cmp.Or(by.SecretAccessKey, bx.SecretAccessKey),
+				SessionToken:    cmp.Or(by.SessionToken, bx.SessionToken),
+			}
+
+		case *SpecAuth:
+			by := y.(*SpecAuth)
+			res := &SpecAuth{
+				Spec:    cmp.Or(by.Spec, bx.Spec),
+				Extract: bx.Extract,
+				TTL:     cmp.Or(by.TTL, bx.TTL),
+			}
+			if by.Extract != nil {
+				res.Extract = by.Extract
+			}
+			return res
 		}
 	}
 	return y
 }
 
-func reduceHeader[H ~map[string][]string](x, y H) H {
+// ReduceHeader applies the headers or query string parameters in y to x.
+// Values of names prefixed with + are appended, values listed under names
+// prefixed with - are removed, and other names replace the values.
+func ReduceHeader[H ~map[string][]string](x, y H) H {
 	for k, v := range y {
 		if name, ok := strings.CutPrefix(k, "+"); ok {
 			x[name] = append(x[name], v...)
@@ -722,8 +925,19 @@ func reduceVars(x, y map[string]any) map[string]any {
 }
 
 func reduceVarSet(x, y []*VarSet) []*VarSet {
-	// TODO Duplicate names between varsets should be consolidated
-	return append(x, y...)
+	for _, v := range y {
+		i := slices.IndexFunc(x, func(u *VarSet) bool {
+			return v.Name != "" && u.Name == v.Name
+		})
+		if i < 0 {
+			x = append(x, v)
+			continue
+		}
+
+		// Entries at closer level (y) win
+		x[i] = mergeVarSet(x[i], v)
+	}
+	return x
 }
 
 func reduceOutput(x, y []*OutputConfig) []*OutputConfig {
@@ -762,7 +976,14 @@ func sameType(x, y any) bool {
 func (*GRPCClient) clientSigil() {}
 func (*HTTPClient) clientSigil() {}
 
-func (*BasicAuth) authSigil() {}
+func (*NoAuth) authSigil()       {}
+func (*BasicAuth) authSigil()    {}
+func (*BearerAuth) authSigil()   {}
+func (*APIKeyAuth) authSigil()   {}
+func (*OAuth2Auth) authSigil()   {}
+func (*SpecAuth) authSigil()     {}
+func (*DigestAuth) authSigil()   {}
+func (*AWSSigV4Auth) authSigil() {}
 
 func (*TemplateOutput) outputFilterSigil() {}
 func (*JMESPathOutput) outputFilterSigil() {}
@@ -5,7 +5,11 @@
 package model
 
 import (
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -36,6 +40,30 @@ var _ = Describe("resolveURL", func() {
 
 })
 
+var _ = Describe("bodyFile", func() {
+
+	It("opens the file when it is read", func() {
+		name := filepath.Join(GinkgoT().TempDir(), "body.json")
+		Expect(os.WriteFile(name, []byte("{}"), 0644)).To(Succeed())
+
+		b := &bodyFile{name: name}
+		Expect(b.ReadCloser).To(BeNil())
+		Expect(io.ReadAll(b)).To(Equal([]byte("{}")))
+		Expect(b.Close()).To(Succeed())
+	})
+
+	It("does not open the file when it is closed before being read", func() {
+		b := &bodyFile{name: "missing.json"}
+		Expect(b.Close()).To(Succeed())
+		Expect(b.ReadCloser).To(BeNil())
+	})
+
+	It("returns the error opening the file when it is read", func() {
+		_, err := io.ReadAll(&bodyFile{name: "missing.json"})
+		Expect(err).To(MatchError(os.ErrNotExist))
+	})
+})
+
 var _ = Describe("reduceAuth", func() {
 	DescribeTable("examples", func(x, y, expected Auth) {
 		Expect(reduceAuth(x, y)).To(Equal(expected))
@@ -52,13 +80,61 @@ var _ = Describe("reduceAuth", func() {
 			&BasicAuth{},
 			&BasicAuth{User: "U", Password: "P"},
 		),
+		Entry(
+			"none: override",
+			&BasicAuth{User: "U", Password: "P"},
+			&NoAuth{},
+			&NoAuth{},
+		),
+		Entry(
+			"bearer: merge",
+			&BearerAuth{Token: "T"},
+			&BearerAuth{},
+			&BearerAuth{Token: "T"},
+		),
+		Entry(
+			"apiKey: merge key",
+			&APIKeyAuth{Key: "K", Header: "X-Key"},
+			&APIKeyAuth{Key: "L"},
+			&APIKeyAuth{Key: "L", Header: "X-Key"},
+		),
+		Entry(
+			"apiKey: location replaced together",
+			&APIKeyAuth{Key: "K", Header: "X-Key"},
+			&APIKeyAuth{Query: "key"},
+			&APIKeyAuth{Key: "K", Query: "key"},
+		),
+		Entry(
+			"oauth2: merge",
+			&OAuth2Auth{TokenURL: "https://auth.example/token", ClientID: "C", Scopes: []string{"read"}},
+			&OAuth2Auth{ClientSecret: "S"},
+			&OAuth2Auth{TokenURL: "https://auth.example/token", ClientID: "C", ClientSecret: "S", Scopes: []string{"read"}},
+		),
+		Entry(
+			"awsSigV4: merge",
+			&AWSSigV4Auth{Region: "us-east-1", Service: "s3"},
+			&AWSSigV4Auth{Region: "eu-west-1", AccessKeyID: "K"},
+			&AWSSigV4Auth{Region: "eu-west-1", Service: "s3", AccessKeyID: "K"},
+		),
+		Entry(
+			"spec: merge",
+			&SpecAuth{Spec: "login.session", Extract: &JMESPathOutput{Query: "token"}},
+			&SpecAuth{TTL: time.Minute},
+			&SpecAuth{Spec: "login.session", Extract: &JMESPathOutput{Query: "token"}, TTL: time.Minute},
+		),
+		Entry(
+			"different types: override",
+			&BasicAuth{User: "U", Password: "P"},
+			&BearerAuth{Token: "T"},
+			&BearerAuth{Token: "T"},
+		),
 	)
 })
 
-var _ = Describe("reduceHeader", func() {
+var _ = Describe("ReduceHeader", func() {
 
 	DescribeTable("examples", func(x, y, expected http.Header) {
-		Expect(reduceHeader(x, y)).To(Equal(expected))
+		Expect(ReduceHeader(x, y)).To(Equal(expected))
 	},
 		Entry(
 			"empty",
@@ -35,3 +35,19 @@ var _ = Describe("metadata", func() {
 		Entry("endpoint", new(model.Endpoint)),
 	)
 })
+
+var _ = Describe("URI templates", func() {
+
+	It("gets the names of the variables", func() {
+		Expect(model.TemplateVarNames("https://{region}.example/{+path}{?a,b*,c:3}")).To(
+			Equal([]string{"region", "path", "a", "b", "c"}),
+		)
+	})
+
+	It("replaces expressions", func() {
+		actual := model.ReplaceTemplateExprs("/users/{id}{?a,b}", func(expr, op string, names []string) string {
+			return fmt.Sprintf("[%s %s %v]", expr, op, names)
+		})
+		Expect(actual).To(Equal("/users/[{id}  [id]][{?a,b} ? [a b]]"))
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"os"
 
 	"github.com/Carbonfrost/joe-cli-http/httpclient"
 	"github.com/Carbonfrost/joe-cli-http/uritemplates"
@@ -96,6 +97,10 @@ func (b *requestBuilder) build(r ResolvedResource) (*Request, error) {
 	)
 
 	body := func() io.ReadCloser {
+		if name := r.Endpoint().BodyFile; name != "" {
+			return &bodyFile{name: name}
+		}
+
 		content := bodyContent(r, combinedVars)
 		if content == nil {
 			return nil
@@ -123,6 +128,40 @@ func (b *requestBuilder) build(r ResolvedResource) (*Request, error) {
 	}, nil
 }
 
+// bodyFile provides the body from a file, which is opened when it is first
+// read. Requests are also evaluated to be logged, which must not open the
+// file or consume stdin.
+type bodyFile struct {
+	name string
+	io.ReadCloser
+}
+
+func (b *bodyFile) Read(p []byte) (int, error) {
+	if b.ReadCloser == nil {
+		b.ReadCloser = openBodyFile(b.name)
+	}
+	return b.ReadCloser.Read(p)
+}
+
+func (b *bodyFile) Close() error {
+	if b.ReadCloser == nil {
+		return nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// openBodyFile opens the file which provides the body, where - is stdin
+func openBodyFile(name string) io.ReadCloser {
+	if name == "-" {
+		return io.NopCloser(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return io.NopCloser(firstReadError{err})
+	}
+	return f
+}
+
 func bodyContent(r ResolvedResource, vars map[string]any) httpclient.Content {
 	if r.Endpoint().Form != nil {
 		return newFormContent(r.Endpoint().Form, vars)
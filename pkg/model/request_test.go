@@ -63,6 +63,40 @@ var _ = Describe("NewRequest", func() {
 		)
 	})
 
+	Context("Auth", func() {
+
+		DescribeTable("examples", func(auth model.Auth, expected model.Auth) {
+			resource := new(modelfakes.FakeResolvedResource)
+			resource.EndpointReturns(&model.Endpoint{
+				Auth: auth,
+				Vars: map[string]any{"token": "s3cr3t"},
+			})
+
+			req, err := model.NewRequest(resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.Auth).To(Equal(expected))
+		},
+			Entry("bearer",
+				&model.BearerAuth{Token: "${token}"},
+				&model.BearerAuth{Token: "s3cr3t"},
+			),
+			Entry("API key",
+				&model.APIKeyAuth{Key: "${var.token}", Query: "key"},
+				&model.APIKeyAuth{Key: "s3cr3t", Query: "key"},
+			),
+		)
+
+		It("disables the auth when none is specified", func() {
+			resource := new(modelfakes.FakeResolvedResource)
+			resource.EndpointReturns(&model.Endpoint{Auth: &model.NoAuth{}})
+			resource.ServiceReturns(&model.Service{Auth: &model.BearerAuth{Token: "t"}})
+
+			req, err := model.NewRequest(resource)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req.Auth).To(BeNil())
+		})
+	})
+
 	Context("Query", func() {
 
 		It("merges into the result", func() {
@@ -230,6 +230,47 @@ func expandAuth(a Auth, e Expander) Auth {
 			User:     expandString(auth.User, e),
 			Password: expandString(auth.Password, e),
 		}
+	case *BearerAuth:
+		return &BearerAuth{
+			Token: expandString(auth.Token, e),
+		}
+	case *APIKeyAuth:
+		return &APIKeyAuth{
+			Key:    expandString(auth.Key, e),
+			Header: auth.Header,
+			Query:  auth.Query,
+		}
+	case *OAuth2Auth:
+		var scopes []string
+		for _, s := range auth.Scopes {
+			scopes = append(scopes, expandString(s, e))
+		}
+		return &OAuth2Auth{
+			TokenURL:     expandString(auth.TokenURL, e),
+			ClientID:     expandString(auth.ClientID, e),
+			ClientSecret: expandString(auth.ClientSecret, e),
+			Scopes:       scopes,
+			RefreshToken: expandString(auth.RefreshToken, e),
+		}
+	case *DigestAuth:
+		return &DigestAuth{
+			User:     expandString(auth.User, e),
+			Password: expandString(auth.Password, e),
+		}
+	case *AWSSigV4Auth:
+		return &AWSSigV4Auth{
+			Region:          expandString(auth.Region, e),
+			Service:         expandString(auth.Service, e),
+			AccessKeyID:     expandString(auth.AccessKeyID, e),
+			SecretAccessKey: expandString(auth.SecretAccessKey, e),
+			SessionToken:    expandString(auth.SessionToken, e),
+		}
+	case *SpecAuth:
+		return &SpecAuth{
+			Spec:    expandString(auth.Spec, e),
+			Extract: auth.Extract,
+			TTL:     auth.TTL,
+		}
 	}
 	return a
 }
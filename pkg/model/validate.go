@@ -14,10 +14,12 @@ import (
 const (
 	badIdentifierDetail  = "A name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes."
 	badQIdentifierDetail = "A package-scoped name must have two parts that each are valid identifiers"
+	badMethodDetail      = "A method must be a valid HTTP token and may contain only letters, digits, and the characters !#$%&'*+-.^_`|~"
 )
 
 var (
 	identifierPattern = regexp.MustCompile(`^(?i)[_a-z][a-z0-9_-]*$`)
+	methodPattern     = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
 )
 
 func Validate(m *Model) error {
@@ -83,6 +85,9 @@ func validateResource(s *Resource) error {
 	if err := validate(s.Endpoints, validateEndpoint); err != nil {
 		return err
 	}
+	if err := checkDuplicateMethods(s.Endpoints); err != nil {
+		return err
+	}
 	return validate(s.Resources, validateResource)
 }
 
@@ -94,9 +99,34 @@ func validateEndpoint(s *Endpoint) error {
 	if err != nil {
 		return err
 	}
+	if err := checkMethod(s.Method); err != nil {
+		return err
+	}
 	return validateVars(s.Vars)
 }
 
+func checkMethod(method string) error {
+	if method == "" || methodPattern.MatchString(method) {
+		return nil
+	}
+	return fmt.Errorf("%q: %s", method, badMethodDetail)
+}
+
+func checkDuplicateMethods(endpoints []*Endpoint) error {
+	seen := map[string]bool{}
+	for _, e := range endpoints {
+		if e == nil {
+			continue
+		}
+		method := strings.ToUpper(e.Method)
+		if seen[method] {
+			return fmt.Errorf("duplicate endpoint for method %s", method)
+		}
+		seen[method] = true
+	}
+	return nil
+}
+
 func validateVars(vars map[string]any) error {
 	for k := range vars {
 		if err := checkName(k); err != nil {
@@ -31,6 +31,16 @@ var _ = Describe("Validate", func() {
 							Name: "valid",
 						},
 					},
+					{
+						Name: "dav",
+						Resource: &model.Resource{
+							Endpoints: []*model.Endpoint{
+								{Method: "GET"},
+								{Method: "PROPFIND"},
+								{Method: "QUERY"},
+							},
+						},
+					},
 				},
 			},
 		),
@@ -96,6 +106,37 @@ var _ = Describe("Validate", func() {
 			},
 			MatchError(ContainSubstring("A name must start with a letter")),
 		),
+		Entry("invalid method",
+			&model.Model{
+				Services: []*model.Service{
+					{
+						Name: "s",
+						Resource: &model.Resource{
+							Endpoints: []*model.Endpoint{
+								{Method: "NOT VALID"},
+							},
+						},
+					},
+				},
+			},
+			MatchError(ContainSubstring("A method must be a valid HTTP token")),
+		),
+		Entry("duplicate method",
+			&model.Model{
+				Services: []*model.Service{
+					{
+						Name: "s",
+						Resource: &model.Resource{
+							Endpoints: []*model.Endpoint{
+								{Method: "GET"},
+								{Method: "get"},
+							},
+						},
+					},
+				},
+			},
+			MatchError(ContainSubstring("duplicate endpoint for method GET")),
+		),
 
 		Entry("unexpected template expression in service",
 			&model.Model{
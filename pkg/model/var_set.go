@@ -0,0 +1,105 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ResolveVarSets gets the var sets which are visible to the resolved resource,
+// which includes the var sets defined in the model. When the resource is nil,
+// only the var sets defined in the model are considered. Var sets that share
+// the same name are consolidated.
+func (m *Model) ResolveVarSets(r ResolvedResource) []*VarSet {
+	res := reduceVarSet(make([]*VarSet, 0), m.VarSets)
+	if r == nil {
+		return res
+	}
+	return reduceVarSet(res, resolveVarSets(r))
+}
+
+// SelectVarSets gets the vars from the given selections of var sets which
+// are visible to the resolved resource. A selection is either SET.NAME,
+// which names the var set and the entry within it, or NAME, in which case
+// the closest var set which defines the entry is used. Vars from
+// later selections take precedence.
+func (m *Model) SelectVarSets(r ResolvedResource, selections ...string) (map[string]any, error) {
+	if len(selections) == 0 {
+		return nil, nil
+	}
+
+	sets := m.ResolveVarSets(r)
+	res := map[string]any{}
+	for _, sel := range selections {
+		vars, ok := selectVarSet(sets, sel)
+		if !ok {
+			return nil, fmt.Errorf("var set not found: %q", sel)
+		}
+		maps.Copy(res, vars)
+	}
+	return res, nil
+}
+
+// Selections gets the names that can be used to select the entries of the
+// var set
+func (v *VarSet) Selections() []string {
+	res := make([]string, 0, len(v.Vars))
+	for _, name := range slices.Sorted(maps.Keys(v.Vars)) {
+		if v.Name == "" {
+			res = append(res, name)
+		} else {
+			res = append(res, v.Name+"."+name)
+		}
+	}
+	return res
+}
+
+func selectVarSet(sets []*VarSet, sel string) (map[string]any, bool) {
+	if i := strings.LastIndex(sel, "."); i > 0 {
+		setName, name := sel[:i], sel[i+1:]
+		for _, s := range sets {
+			if s.Name == setName {
+				vars, ok := s.Vars[name]
+				return vars, ok
+			}
+		}
+	}
+
+	// Closest var set wins
+	for _, s := range slices.Backward(sets) {
+		if vars, ok := s.Vars[sel]; ok {
+			return vars, true
+		}
+	}
+	return nil, false
+}
+
+func mergeVarSet(x, y *VarSet) *VarSet {
+	vars := maps.Clone(x.Vars)
+	if vars == nil {
+		vars = map[string]map[string]any{}
+	}
+	for name, v := range y.Vars {
+		merged := maps.Clone(vars[name])
+		if merged == nil {
+			merged = map[string]any{}
+		}
+		maps.Copy(merged, v)
+		vars[name] = merged
+	}
+
+	return &VarSet{
+		Name:        x.Name,
+		Comment:     cmp.Or(y.Comment, x.Comment),
+		Title:       cmp.Or(y.Title, x.Title),
+		Description: cmp.Or(y.Description, x.Description),
+		Links:       slices.Concat(x.Links, y.Links),
+		Vars:        vars,
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package model_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("VarSet", func() {
+
+	var subject = func() *model.Model {
+		return model.New(
+			&config.File{
+				VarSets: []config.VarSet{
+					{
+						Name: "customers",
+						Metadata: config.Metadata{
+							Title: "Global",
+						},
+						Vars: map[string]map[string]any{
+							"one": {"id": 1, "region": "us"},
+							"two": {"id": 2},
+						},
+					},
+					{
+						Name: "users",
+						Vars: map[string]map[string]any{
+							"admin": {"user": "root"},
+						},
+					},
+				},
+				Services: []config.Service{
+					{
+						Name: "a",
+						VarSets: []config.VarSet{
+							{
+								Name: "customers",
+								Vars: map[string]map[string]any{
+									"one":   {"id": 11},
+									"three": {"id": 3},
+								},
+							},
+						},
+						Resources: []config.Resource{
+							{
+								Name: "r",
+								Get:  &config.Endpoint{},
+							},
+						},
+					},
+				},
+			},
+		)
+	}
+
+	Describe("SelectVarSets", func() {
+
+		DescribeTable("examples", func(selections []string, expected types.GomegaMatcher) {
+			mo := subject()
+			r, err := mo.Resolve(model.ServiceSpec{"a", "r"}, "", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			vars, err := mo.SelectVarSets(r, selections...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(vars).To(expected)
+		},
+			Entry("qualified", []string{"customers.two"}, Equal(map[string]any{"id": 2})),
+			Entry("unqualified", []string{"admin"}, Equal(map[string]any{"user": "root"})),
+			Entry("consolidated across levels", []string{"customers.one"}, Equal(map[string]any{"id": 11, "region": "us"})),
+			Entry("defined at closer level", []string{"three"}, Equal(map[string]any{"id": 3})),
+			Entry("later selection wins", []string{"one", "two"}, Equal(map[string]any{"id": 2, "region": "us"})),
+			Entry("none", []string{}, BeNil()),
+		)
+
+		It("returns an error when the var set is not found", func() {
+			mo := subject()
+			_, err := mo.SelectVarSets(nil, "customers.three")
+			Expect(err).To(MatchError(`var set not found: "customers.three"`))
+		})
+	})
+
+	Describe("ResolveVarSets", func() {
+
+		It("consolidates var sets with the same name", func() {
+			mo := subject()
+			r, _ := mo.Resolve(model.ServiceSpec{"a", "r"}, "", "")
+
+			sets := mo.ResolveVarSets(r)
+			Expect(sets).To(HaveLen(2))
+			Expect(sets[0].Title).To(Equal("Global"))
+			Expect(sets[0].Selections()).To(Equal([]string{"customers.one", "customers.three", "customers.two"}))
+		})
+
+		It("does not modify the model", func() {
+			mo := subject()
+			r, _ := mo.Resolve(model.ServiceSpec{"a", "r"}, "", "")
+
+			_ = mo.ResolveVarSets(r)
+			Expect(mo.VarSets[0].Vars).To(HaveLen(2))
+			Expect(mo.VarSets[0].Vars["one"]).To(HaveKeyWithValue("id", 1))
+		})
+	})
+})
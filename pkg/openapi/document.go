@@ -0,0 +1,190 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package openapi converts between OpenAPI 3 documents and Pastiche
+// service configuration.
+package openapi
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Document is the subset of an OpenAPI 3.0 or 3.1 document which is
+// meaningful to Pastiche
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths,omitempty"`
+	Components *Components           `json:"components,omitempty"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+type ServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+type PathItem struct {
+	Summary     string     `json:"summary,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Get         *Operation `json:"get,omitempty"`
+	Put         *Operation `json:"put,omitempty"`
+	Post        *Operation `json:"post,omitempty"`
+	Delete      *Operation `json:"delete,omitempty"`
+	Options     *Operation `json:"options,omitempty"`
+	Head        *Operation `json:"head,omitempty"`
+	Patch       *Operation `json:"patch,omitempty"`
+	Trace       *Operation `json:"trace,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                 `json:"operationId,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses,omitempty"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      any    `json:"schema,omitempty"`
+	Example     any    `json:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema  any `json:"schema,omitempty"`
+	Example any `json:"example,omitempty"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Name        string      `json:"name,omitempty"`
+	In          string      `json:"in,omitempty"`
+	Scheme      string      `json:"scheme,omitempty"`
+	Flows       *OAuthFlows `json:"flows,omitempty"`
+}
+
+type OAuthFlows struct {
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// SecurityRequirement names the security schemes which apply and the
+// scopes they require
+type SecurityRequirement map[string][]string
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Parse parses an OpenAPI document in either JSON or YAML
+func Parse(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q", doc.OpenAPI)
+	}
+	return &doc, nil
+}
+
+// Operations iterates the operations of the path item by method
+func (p *PathItem) Operations() iter.Seq2[string, *Operation] {
+	return func(yield func(string, *Operation) bool) {
+		for _, method := range methods {
+			if op := *p.operationRef(method); op != nil {
+				if !yield(method, op) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SetOperation sets the operation for the method, which must be one of
+// the methods supported by OpenAPI
+func (p *PathItem) SetOperation(method string, op *Operation) bool {
+	ref := p.operationRef(method)
+	if ref == nil {
+		return false
+	}
+	*ref = op
+	return true
+}
+
+var methods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+func (p *PathItem) operationRef(method string) **Operation {
+	switch method {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "POST":
+		return &p.Post
+	case "DELETE":
+		return &p.Delete
+	case "OPTIONS":
+		return &p.Options
+	case "HEAD":
+		return &p.Head
+	case "PATCH":
+		return &p.Patch
+	case "TRACE":
+		return &p.Trace
+	}
+	return nil
+}
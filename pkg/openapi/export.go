@@ -0,0 +1,344 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/internal/log"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+)
+
+// Version is the version of OpenAPI which is exported
+const Version = "3.1.0"
+
+// FromModel converts the service to an OpenAPI document. The URI templates
+// of each resource and its ancestors are joined to form paths, and each
+// endpoint becomes an operation. Headers and query strings become
+// parameters, and bodies become examples.
+func FromModel(svc *model.Service) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:       cmp.Or(svc.Title, svc.Name),
+			Description: svc.Description,
+			Version:     "1.0.0",
+		},
+		Servers: exportServers(svc.Servers),
+		Paths:   map[string]*PathItem{},
+	}
+
+	e := &exporter{doc: doc, serviceAuth: svc.Auth}
+	doc.Security = e.security(svc.Auth)
+	if svc.Resource != nil {
+		e.resource(svc.Resource, nil)
+	}
+
+	tags := map[string]bool{}
+	for _, item := range doc.Paths {
+		for _, op := range item.Operations() {
+			for _, t := range op.Tags {
+				tags[t] = true
+			}
+		}
+	}
+	for _, t := range slices.Sorted(maps.Keys(tags)) {
+		doc.Tags = append(doc.Tags, Tag{Name: t})
+	}
+	return doc
+}
+
+type exporter struct {
+	doc         *Document
+	serviceAuth model.Auth
+}
+
+func exportServers(servers []*model.Server) []Server {
+	var res []Server
+	for _, s := range servers {
+		server := Server{
+			URL:         s.BaseURL,
+			Description: cmp.Or(s.Description, s.Title, s.Name),
+		}
+		for _, name := range model.TemplateVarNames(s.BaseURL) {
+			if server.Variables == nil {
+				server.Variables = map[string]ServerVariable{}
+			}
+			var value string
+			if v, ok := s.Vars[name]; ok {
+				value = fmt.Sprint(v)
+			}
+			server.Variables[name] = ServerVariable{Default: value}
+		}
+		res = append(res, server)
+	}
+	return res
+}
+
+func (e *exporter) resource(r *model.Resource, lineage []*model.Resource) {
+	lineage = append(lineage, r)
+
+	// Resources without endpoints of their own don't become paths
+	if len(r.Endpoints) > 0 && !r.HasImplicitEndpoint() {
+		p, params := exportPath(lineage)
+		item, ok := e.doc.Paths[p]
+		if !ok {
+			item = &PathItem{}
+		}
+
+		for _, ep := range r.Endpoints {
+			op := e.operation(lineage, ep)
+			op.Parameters = append(slices.Clone(params), op.Parameters...)
+
+			// OpenAPI 3.1 only has operations for the standard methods
+			method := strings.ToUpper(cmp.Or(ep.Method, "GET"))
+			if !item.SetOperation(method, op) {
+				log.Warnf("warning: skipped %s %s because OpenAPI does not support the method", method, p)
+				continue
+			}
+			ok = true
+		}
+
+		if ok {
+			item.Summary = cmp.Or(item.Summary, r.Title)
+			item.Description = cmp.Or(item.Description, r.Description)
+			e.doc.Paths[p] = item
+		}
+	}
+
+	for _, child := range r.Resources {
+		e.resource(child, lineage)
+	}
+}
+
+func (e *exporter) operation(lineage []*model.Resource, ep *model.Endpoint) *Operation {
+	headers := map[string][]string{}
+	query := map[string][]string{}
+	var (
+		auth model.Auth
+		body any
+	)
+	for _, r := range lineage {
+		headers = model.ReduceHeader(headers, r.Headers)
+		query = model.ReduceHeader(query, r.Query)
+		if r.Auth != nil {
+			auth = r.Auth
+		}
+		if r.Body != nil {
+			body = r.Body
+		}
+	}
+	headers = model.ReduceHeader(headers, ep.Headers)
+	query = model.ReduceHeader(query, ep.Query)
+	if ep.Auth != nil {
+		auth = ep.Auth
+	}
+	if ep.Body != nil {
+		body = ep.Body
+	}
+
+	op := &Operation{
+		OperationID: ep.Name,
+		Summary:     ep.Title,
+		Description: ep.Description,
+		Tags:        ep.Tags,
+		Responses:   exportResponses(ep.Expect),
+	}
+
+	contentType := "application/json"
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		if len(headers[name]) == 0 {
+			continue
+		}
+		if http.CanonicalHeaderKey(name) == "Content-Type" {
+			contentType = cmp.Or(strings.Join(headers[name], ","), contentType)
+			continue
+		}
+		op.Parameters = append(op.Parameters, exportParameter(name, "header", headers[name]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		if len(query[name]) == 0 {
+			continue
+		}
+		op.Parameters = append(op.Parameters, exportParameter(name, "query", query[name]))
+	}
+
+	switch {
+	case body != nil:
+		op.RequestBody = &RequestBody{
+			Content: map[string]MediaType{
+				contentType: {Example: body},
+			},
+		}
+	case len(ep.Form) > 0:
+		op.RequestBody = &RequestBody{
+			Content: map[string]MediaType{
+				"application/x-www-form-urlencoded": {Example: flatten(ep.Form)},
+			},
+		}
+	}
+
+	if auth != nil && auth != e.serviceAuth {
+		security := e.security(auth)
+		op.Security = &security
+	}
+	return op
+}
+
+func exportResponses(expect *model.Expectation) map[string]*Response {
+	if expect == nil || len(expect.Status) == 0 {
+		return map[string]*Response{
+			"default": {Description: "Response"},
+		}
+	}
+	res := map[string]*Response{}
+	for _, s := range expect.Status {
+		res[strconv.Itoa(s)] = &Response{Description: http.StatusText(s)}
+	}
+	return res
+}
+
+func exportParameter(name, in string, values []string) Parameter {
+	p := Parameter{
+		Name:   name,
+		In:     in,
+		Schema: map[string]any{"type": "string"},
+	}
+	if len(values) > 0 {
+		p.Example = strings.Join(values, ",")
+	}
+	return p
+}
+
+// exportPath joins the URI templates of the lineage into a path. Template
+// expressions which expand into the query string become query parameters;
+// other expressions become path parameters.
+func exportPath(lineage []*model.Resource) (string, []Parameter) {
+	var segments []string
+	for _, r := range lineage {
+		if r.URITemplate != nil {
+			segments = append(segments, r.URITemplate.String())
+		}
+	}
+	template := "/" + path.Join(segments...)
+	template = strings.TrimPrefix(path.Clean(template), "/")
+
+	var params []Parameter
+	seen := map[string]bool{}
+	addParam := func(name, in string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		params = append(params, Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path",
+			Schema:   map[string]any{"type": "string"},
+		})
+	}
+
+	p := model.ReplaceTemplateExprs(template, func(_, op string, names []string) string {
+		switch op {
+		case "?", "&":
+			for _, name := range names {
+				addParam(name, "query")
+			}
+			return ""
+		}
+
+		// Only the separator is kept from operators which add one
+		var prefix string
+		if op == "/" || op == "." {
+			prefix = op
+		}
+		var res []string
+		for _, name := range names {
+			addParam(name, "path")
+			res = append(res, prefix+"{"+name+"}")
+		}
+		return strings.Join(res, "")
+	})
+	return "/" + p, params
+}
+
+func (e *exporter) security(a model.Auth) []SecurityRequirement {
+	if _, ok := a.(*model.NoAuth); ok {
+		return []SecurityRequirement{}
+	}
+	name, scheme, scopes := exportAuth(a)
+	if scheme == nil {
+		return nil
+	}
+	if e.doc.Components == nil {
+		e.doc.Components = &Components{SecuritySchemes: map[string]*SecurityScheme{}}
+	}
+	name = e.schemeName(name, scheme)
+	e.doc.Components.SecuritySchemes[name] = scheme
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return []SecurityRequirement{{name: scopes}}
+}
+
+// schemeName derives a unique name for the security scheme. The name is
+// shared by schemes which are the same.
+func (e *exporter) schemeName(name string, scheme *SecurityScheme) string {
+	res := name
+	for i := 2; ; i++ {
+		existing, ok := e.doc.Components.SecuritySchemes[res]
+		if !ok || reflect.DeepEqual(existing, scheme) {
+			return res
+		}
+		res = name + strconv.Itoa(i)
+	}
+}
+
+func exportAuth(a model.Auth) (string, *SecurityScheme, []string) {
+	switch auth := a.(type) {
+	case *model.BasicAuth:
+		return "basicAuth", &SecurityScheme{Type: "http", Scheme: "basic"}, nil
+	case *model.BearerAuth, *model.SpecAuth:
+		return "bearerAuth", &SecurityScheme{Type: "http", Scheme: "bearer"}, nil
+	case *model.DigestAuth:
+		return "digestAuth", &SecurityScheme{Type: "http", Scheme: "digest"}, nil
+	case *model.APIKeyAuth:
+		if auth.Query != "" {
+			return "apiKey", &SecurityScheme{Type: "apiKey", In: "query", Name: auth.Query}, nil
+		}
+		return "apiKey", &SecurityScheme{Type: "apiKey", In: "header", Name: auth.HeaderName()}, nil
+	case *model.OAuth2Auth:
+		scopes := map[string]string{}
+		for _, s := range auth.Scopes {
+			scopes[s] = ""
+		}
+		return "oauth2", &SecurityScheme{
+			Type: "oauth2",
+			Flows: &OAuthFlows{
+				ClientCredentials: &OAuthFlow{
+					TokenURL: auth.TokenURL,
+					Scopes:   scopes,
+				},
+			},
+		}, auth.Scopes
+	}
+	return "", nil, nil
+}
+
+func flatten(m map[string][]string) map[string]any {
+	res := map[string]any{}
+	for k, v := range m {
+		res[k] = strings.Join(v, ",")
+	}
+	return res
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi_test
+
+import (
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/model"
+	"github.com/Carbonfrost/pastiche/pkg/openapi"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("FromModel", func() {
+
+	var subject = func() *openapi.Document {
+		mo := model.New(&config.File{
+			Service: &config.Service{
+				Name: "petstore",
+				Metadata: config.Metadata{
+					Title: "Petstore",
+				},
+				Servers: []config.Server{
+					{
+						Name:    "production",
+						BaseURL: "https://{region}.petstore.example/v1",
+						Vars:    map[string]any{"region": "us"},
+					},
+				},
+				Auth: &config.Auth{
+					Bearer: &config.BearerAuth{Token: "${token}"},
+				},
+				Resources: []config.Resource{
+					{
+						Name:    "pets",
+						URI:     "pets{?limit}",
+						Headers: config.Header{"Accept": {"application/json"}},
+						Get: &config.Endpoint{
+							Name: "listPets",
+							Metadata: config.Metadata{
+								Title: "List all pets",
+								Tags:  []string{"pets"},
+							},
+							Query: config.Header{"sort": {"name"}},
+						},
+						Post: &config.Endpoint{
+							Headers: config.Header{"Content-Type": {"application/json"}},
+							Body:    map[string]any{"name": "Rex"},
+							Auth: &config.Auth{
+								APIKey: &config.APIKeyAuth{Key: "k", Header: "X-Pet-Key"},
+							},
+							Expect: &config.Expect{Status: []int{201}},
+						},
+						Resources: []config.Resource{
+							{
+								Name:    "pet",
+								URI:     "{petId}",
+								Headers: config.Header{"X-Trace": {"a", "b"}},
+								Delete: &config.Endpoint{
+									Metadata: config.Metadata{Tags: []string{"admin"}},
+									Headers:  config.Header{"-X-Trace": {"a"}},
+									Auth: &config.Auth{
+										APIKey: &config.APIKeyAuth{Key: "k", Query: "key"},
+									},
+								},
+							},
+							{
+								Name: "properties",
+								URI:  "properties",
+								Methods: config.Methods{
+									"PROPFIND": &config.Endpoint{},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		svc, _ := mo.Service("petstore")
+		return openapi.FromModel(svc)
+	}
+
+	It("converts the info and servers", func() {
+		doc := subject()
+		Expect(doc.OpenAPI).To(Equal("3.1.0"))
+		Expect(doc.Info.Title).To(Equal("Petstore"))
+		Expect(doc.Servers).To(Equal([]openapi.Server{
+			{
+				URL:         "https://{region}.petstore.example/v1",
+				Description: "production",
+				Variables: map[string]openapi.ServerVariable{
+					"region": {Default: "us"},
+				},
+			},
+		}))
+	})
+
+	It("joins URI templates into paths", func() {
+		Expect(subject().Paths).To(And(
+			HaveKey("/pets"),
+			HaveKey("/pets/{petId}"),
+			HaveLen(2),
+		))
+	})
+
+	It("converts parameters", func() {
+		op := subject().Paths["/pets"].Get
+		Expect(op.Parameters).To(Equal([]openapi.Parameter{
+			{Name: "limit", In: "query", Schema: map[string]any{"type": "string"}},
+			{Name: "Accept", In: "header", Schema: map[string]any{"type": "string"}, Example: "application/json"},
+			{Name: "sort", In: "query", Schema: map[string]any{"type": "string"}, Example: "name"},
+		}))
+		Expect(subject().Paths["/pets/{petId}"].Delete.Parameters).To(ContainElement(
+			openapi.Parameter{Name: "petId", In: "path", Required: true, Schema: map[string]any{"type": "string"}},
+		))
+	})
+
+	It("removes only the listed header values", func() {
+		op := subject().Paths["/pets/{petId}"].Delete
+		Expect(op.Parameters).To(ContainElement(
+			openapi.Parameter{Name: "X-Trace", In: "header", Schema: map[string]any{"type": "string"}, Example: "b"},
+		))
+	})
+
+	It("converts operations", func() {
+		op := subject().Paths["/pets"].Get
+		Expect(op).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"OperationID": Equal("listPets"),
+			"Summary":     Equal("List all pets"),
+			"Tags":        Equal([]string{"pets"}),
+			"Responses":   HaveKey("default"),
+			"Security":    BeNil(),
+		})))
+	})
+
+	It("converts bodies to examples", func() {
+		op := subject().Paths["/pets"].Post
+		Expect(op.RequestBody.Content).To(Equal(map[string]openapi.MediaType{
+			"application/json": {Example: map[string]any{"name": "Rex"}},
+		}))
+		Expect(op.Responses).To(HaveKeyWithValue("201", &openapi.Response{Description: "Created"}))
+	})
+
+	It("converts auth to security schemes", func() {
+		doc := subject()
+		Expect(doc.Security).To(Equal([]openapi.SecurityRequirement{{"bearerAuth": {}}}))
+		Expect(doc.Components.SecuritySchemes).To(Equal(map[string]*openapi.SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+			"apiKey":     {Type: "apiKey", In: "header", Name: "X-Pet-Key"},
+			"apiKey2":    {Type: "apiKey", In: "query", Name: "key"},
+		}))
+		Expect(*doc.Paths["/pets"].Post.Security).To(Equal([]openapi.SecurityRequirement{{"apiKey": {}}}))
+		Expect(*doc.Paths["/pets/{petId}"].Delete.Security).To(Equal([]openapi.SecurityRequirement{{"apiKey2": {}}}))
+	})
+
+	It("collects tags", func() {
+		Expect(subject().Tags).To(Equal([]openapi.Tag{{Name: "admin"}, {Name: "pets"}}))
+	})
+})
@@ -0,0 +1,201 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+)
+
+// ToConfig converts the document to a service with the given name. Servers
+// become servers, paths become nested resources with one resource for each
+// segment of the path, and operations become endpoints. Security schemes
+// are converted to auth where Pastiche has an equivalent.
+func ToConfig(doc *Document, name string) *config.Service {
+	svc := &config.Service{
+		Name: name,
+		Metadata: config.Metadata{
+			Title:       doc.Info.Title,
+			Description: doc.Info.Description,
+		},
+		Servers: configServers(doc.Servers),
+		Auth:    configSecurity(doc, doc.Security),
+	}
+
+	for _, p := range slices.Sorted(maps.Keys(doc.Paths)) {
+		svc.Resources = addPath(doc, svc.Resources, segments(p), doc.Paths[p])
+	}
+	return svc
+}
+
+func configServers(servers []Server) []config.Server {
+	var res []config.Server
+	for i, s := range servers {
+		var vars map[string]any
+		for k, v := range s.Variables {
+			if vars == nil {
+				vars = map[string]any{}
+			}
+			vars[k] = v.Default
+		}
+
+		res = append(res, config.Server{
+			Name:    serverName(i, s),
+			BaseURL: s.URL,
+			Vars:    vars,
+			Metadata: config.Metadata{
+				Description: s.Description,
+			},
+		})
+	}
+	return res
+}
+
+func serverName(i int, s Server) string {
+	if name := strings.ToLower(config.Identifier(s.Description)); name != "" {
+		return name
+	}
+	if i == 0 {
+		return "default"
+	}
+	return fmt.Sprintf("server%d", i+1)
+}
+
+func segments(path string) []string {
+	var res []string
+	for s := range strings.SplitSeq(path, "/") {
+		if s != "" {
+			res = append(res, s)
+		}
+	}
+	if len(res) == 0 {
+		return []string{"/"}
+	}
+	return res
+}
+
+func addPath(doc *Document, resources []config.Resource, segments []string, item *PathItem) []config.Resource {
+	seg := segments[0]
+	i := slices.IndexFunc(resources, func(r config.Resource) bool { return r.URI == seg })
+	if i < 0 {
+		resources = append(resources, config.Resource{
+			Name: cmp.Or(config.Identifier(seg), "root"),
+			URI:  seg,
+		})
+		i = len(resources) - 1
+	}
+
+	r := &resources[i]
+	if len(segments) > 1 {
+		r.Resources = addPath(doc, r.Resources, segments[1:], item)
+		return resources
+	}
+
+	r.Title = item.Summary
+	r.Description = item.Description
+	for method, op := range item.Operations() {
+		r.SetEndpoint(method, configEndpoint(doc, op))
+	}
+	return resources
+}
+
+func configEndpoint(doc *Document, op *Operation) *config.Endpoint {
+	ep := &config.Endpoint{
+		Name: config.Identifier(op.OperationID),
+		Metadata: config.Metadata{
+			Title:       op.Summary,
+			Description: op.Description,
+			Tags:        op.Tags,
+		},
+	}
+	if s := op.Security; s != nil && len(*s) == 0 {
+		// An empty list of requirements means that the operation doesn't
+		// use the auth of the document
+		ep.Auth = &config.Auth{None: true}
+	} else if s != nil {
+		ep.Auth = configSecurity(doc, *s)
+	}
+	if op.RequestBody != nil {
+		if mt, ok := op.RequestBody.Content["application/json"]; ok {
+			ep.Body = mt.Example
+		}
+	}
+	return ep
+}
+
+// configSecurity converts the first security requirement that has an
+// equivalent auth
+func configSecurity(doc *Document, security []SecurityRequirement) *config.Auth {
+	if doc.Components == nil {
+		return nil
+	}
+	for _, req := range security {
+		for _, name := range slices.Sorted(maps.Keys(req)) {
+			scheme, ok := doc.Components.SecuritySchemes[name]
+			if !ok {
+				continue
+			}
+			if auth := configAuth(scheme, req[name]); auth != nil {
+				return auth
+			}
+		}
+	}
+	return nil
+}
+
+func configAuth(s *SecurityScheme, scopes []string) *config.Auth {
+	switch s.Type {
+	case "http":
+		switch strings.ToLower(s.Scheme) {
+		case "basic":
+			return &config.Auth{
+				Basic: &config.BasicAuth{User: "${user}", Password: "${password}"},
+			}
+		case "bearer":
+			return &config.Auth{
+				Bearer: &config.BearerAuth{Token: "${token}"},
+			}
+		case "digest":
+			return &config.Auth{
+				Digest: &config.DigestAuth{User: "${user}", Password: "${password}"},
+			}
+		}
+
+	case "apiKey":
+		a := &config.APIKeyAuth{Key: "${apiKey}"}
+		switch s.In {
+		case "header":
+			a.Header = s.Name
+		case "query":
+			a.Query = s.Name
+		default:
+			return nil
+		}
+		return &config.Auth{APIKey: a}
+
+	case "oauth2":
+		if s.Flows == nil || s.Flows.ClientCredentials == nil {
+			return nil
+		}
+		flow := s.Flows.ClientCredentials
+		if len(scopes) == 0 {
+			scopes = slices.Sorted(maps.Keys(flow.Scopes))
+		}
+		return &config.Auth{
+			OAuth2: &config.OAuth2Auth{
+				TokenURL:     flow.TokenURL,
+				ClientID:     "${clientId}",
+				ClientSecret: "${clientSecret}",
+				Scopes:       scopes,
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The Pastiche Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi_test
+
+import (
+	"os"
+
+	"github.com/Carbonfrost/pastiche/pkg/config"
+	"github.com/Carbonfrost/pastiche/pkg/openapi"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("ToConfig", func() {
+
+	var subject = func() *config.Service {
+		data, err := os.ReadFile("testdata/petstore.yml")
+		Expect(err).NotTo(HaveOccurred())
+
+		doc, err := openapi.Parse(data)
+		Expect(err).NotTo(HaveOccurred())
+		return openapi.ToConfig(doc, "petstore")
+	}
+
+	It("converts the info", func() {
+		svc := subject()
+		Expect(svc.Name).To(Equal("petstore"))
+		Expect(svc.Title).To(Equal("Petstore"))
+		Expect(svc.Description).To(Equal("A sample pet store"))
+	})
+
+	It("converts servers", func() {
+		Expect(subject().Servers).To(Equal([]config.Server{
+			{
+				Name:     "production",
+				BaseURL:  "https://{region}.petstore.example/v1",
+				Vars:     map[string]any{"region": "us"},
+				Metadata: config.Metadata{Description: "Production"},
+			},
+			{
+				Name:    "server2",
+				BaseURL: "http://localhost:8080/v1",
+			},
+		}))
+	})
+
+	It("converts paths to nested resources", func() {
+		svc := subject()
+		Expect(svc.Resources).To(HaveLen(2))
+		Expect(svc.Resources[0]).To(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("pets"),
+			"URI":  Equal("pets"),
+			"Resources": ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("petId"),
+				"URI":  Equal("{petId}"),
+			})),
+		}))
+		Expect(svc.Resources[1].Resources[0]).To(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("inventory"),
+			"URI":  Equal("inventory"),
+		}))
+	})
+
+	DescribeTable("operations", func(path []int, method string, expected types.GomegaMatcher) {
+		r := subject().Resources[path[0]]
+		for _, i := range path[1:] {
+			r = r.Resources[i]
+		}
+		ep, ok := r.Endpoint(method)
+		Expect(ok).To(BeTrue())
+		Expect(ep).To(expected)
+	},
+		Entry("metadata", []int{0}, "GET", PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("listPets"),
+			"Metadata": Equal(config.Metadata{
+				Title: "List all pets",
+				Tags:  []string{"pets"},
+			}),
+		}))),
+		Entry("description", []int{0, 0}, "GET", PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name":     Equal("showPetById"),
+			"Metadata": HaveField("Description", "Info for a specific pet"),
+		}))),
+		Entry("example body", []int{0}, "POST", PointTo(MatchFields(IgnoreExtras, Fields{
+			"Body": Equal(map[string]any{"name": "Rex"}),
+		}))),
+	)
+
+	DescribeTable("security", func(path []int, method string, expected *config.Auth) {
+		svc := subject()
+		if path == nil {
+			Expect(svc.Auth).To(Equal(expected))
+			return
+		}
+		r := svc.Resources[path[0]]
+		for _, i := range path[1:] {
+			r = r.Resources[i]
+		}
+		ep, _ := r.Endpoint(method)
+		Expect(ep.Auth).To(Equal(expected))
+	},
+		Entry("API key", nil, "", &config.Auth{
+			APIKey: &config.APIKeyAuth{Key: "${apiKey}", Header: "X-Pet-Key"},
+		}),
+		Entry("OAuth2", []int{0}, "POST", &config.Auth{
+			OAuth2: &config.OAuth2Auth{
+				TokenURL:     "https://petstore.example/oauth/token",
+				ClientID:     "${clientId}",
+				ClientSecret: "${clientSecret}",
+				Scopes:       []string{"write:pets"},
+			},
+		}),
+		Entry("basic", []int{1, 0}, "GET", &config.Auth{
+			Basic: &config.BasicAuth{User: "${user}", Password: "${password}"},
+		}),
+		Entry("inherited", []int{0}, "GET", (*config.Auth)(nil)),
+		Entry("none", []int{0, 0}, "GET", &config.Auth{None: true}),
+	)
+
+	It("returns an error for unsupported versions", func() {
+		_, err := openapi.Parse([]byte(`swagger: "2.0"`))
+		Expect(err).To(MatchError(`unsupported OpenAPI version ""`))
+	})
+})
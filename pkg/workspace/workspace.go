@@ -6,6 +6,7 @@
 package workspace
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -174,7 +175,7 @@ func (w *Workspace) loadFiles(root string) error {
 		}
 
 		// TODO This should follow rules specified in .ignore files instead
-		if d.IsDir() && d.Name() == "logs" {
+		if d.IsDir() && (d.Name() == "logs" || d.Name() == "cache") {
 			return fs.SkipDir
 		}
 		if strings.HasPrefix(d.Name(), "_") {
@@ -207,10 +208,149 @@ func (w *Workspace) loadFiles(root string) error {
 	})
 }
 
+// Format rewrites the configuration files in the workspace in canonical
+// form. The names of files which were changed are returned. When check
+// is set, the files are not written.
+func (w *Workspace) Format(check bool) ([]string, error) {
+	var changed []string
+	err := w.walkConfigFiles(func(name string, _ *config.File) error {
+		filename := w.configFile(name)
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		data, err = config.FormatSource(data, name)
+		if err != nil {
+			// Files such as .http files can be read but not written
+			if errors.Is(err, config.ErrUnsupportedFileFormat) {
+				return nil
+			}
+			return err
+		}
+
+		ok, err := writeChanged(filename, data, check)
+		if ok {
+			changed = append(changed, filepath.Join(".pastiche", name))
+		}
+		return err
+	})
+	return changed, err
+}
+
+// Import merges the service into the configuration file in the workspace
+// which defines the service with the same name. Files which cannot be
+// written, such as .http files, are skipped. If no other file defines the
+// service, a new file is created. The name of the file which was written
+// is returned.
+func (w *Workspace) Import(svc *config.Service) (string, error) {
+	var (
+		target string
+		data   []byte
+	)
+	err := w.walkConfigFiles(func(name string, file *config.File) error {
+		if data != nil || file.FindService(svc.Name) == nil {
+			return nil
+		}
+
+		original, err := os.ReadFile(w.configFile(name))
+		if err != nil {
+			return err
+		}
+		data, err = config.MergeServiceSource(original, name, svc)
+		if err != nil {
+			// Files such as .http files can be read but not written
+			if errors.Is(err, config.ErrUnsupportedFileFormat) {
+				return nil
+			}
+			return fmt.Errorf("%s: %w", filepath.Join(".pastiche", name), err)
+		}
+		target = name
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if data == nil {
+		target = svc.Name + ".yml"
+		data, err = config.Format(&config.File{Service: svc}, target)
+		if err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(w.configFile("."), 0755); err != nil {
+			return "", err
+		}
+	}
+
+	_, err = writeChanged(w.configFile(target), data, false)
+	return filepath.Join(".pastiche", target), err
+}
+
+func (w *Workspace) configFile(name string) string {
+	return filepath.Join(w.Dir(), ".pastiche", name)
+}
+
+// walkConfigFiles reads each of the configuration files in the workspace
+// without resolving the files which they source
+func (w *Workspace) walkConfigFiles(fn func(string, *config.File) error) error {
+	rootFS := os.DirFS(w.configFile("."))
+
+	err := fs.WalkDir(rootFS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && (d.Name() == "logs" || d.Name() == "cache") {
+			return fs.SkipDir
+		}
+
+		// Files which are sourced by other files are skipped because they
+		// don't contain a complete file
+		if strings.HasPrefix(d.Name(), "_") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		file, err := config.ReadFile(rootFS, name)
+		if err != nil {
+			if errors.Is(err, config.ErrUnsupportedFileFormat) {
+				return nil
+			}
+			return err
+		}
+		return fn(name, file)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// writeChanged writes the data to the file unless it is unchanged or check
+// is set. Whether the file changed is returned.
+func writeChanged(filename string, data []byte, check bool) (bool, error) {
+	original, err := os.ReadFile(filename)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	if bytes.Equal(original, data) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+	return true, os.WriteFile(filename, data, 0644)
+}
+
 func (w *Workspace) Env() iter.Seq2[string, string] {
 	m := map[string]string{
 		"PASTICHE_DIR":        w.Dir(),
 		"PASTICHE_LOG_DIR":    w.LogDir(),
+		"PASTICHE_CACHE_DIR":  w.CacheDir(),
 		"PASTICHE_CONFIG_DIR": w.ConfigDir(),
 	}
 
@@ -239,6 +379,15 @@ func (w *Workspace) LogDir() string {
 	return logDir
 }
 
+// CacheDir gets the directory which is used to cache data such as
+// credentials
+func (w *Workspace) CacheDir() string {
+	cacheDir := filepath.Join(w.Dir(), ".pastiche", "cache")
+	os.MkdirAll(cacheDir, 0700)
+
+	return cacheDir
+}
+
 func (w *Workspace) ClearLogDir() error {
 	err := os.RemoveAll(w.LogDir())
 	if err != nil {
@@ -5,6 +5,8 @@
 package workspace
 
 import (
+	"fmt"
+
 	cli "github.com/Carbonfrost/joe-cli"
 	"github.com/Carbonfrost/joe-cli/extensions/bind"
 )
@@ -44,6 +46,38 @@ func Env() cli.Action {
 	)
 }
 
+// Fmt provides the action to format the configuration files in the workspace
+func Fmt() cli.Action {
+	return cli.Pipeline(
+		cli.Prototype{
+			HelpText: "Rewrite configuration files in the workspace in canonical form",
+			Uses: cli.AddFlags([]*cli.Flag{
+				{
+					Name:     "check",
+					HelpText: "Report files that are not formatted without rewriting them",
+					Value:    new(bool),
+				},
+			}...),
+		},
+		cli.At(cli.ActionTiming, cli.ActionFunc(formatFiles)),
+	)
+}
+
+func formatFiles(c *cli.Context) error {
+	check := c.Bool("check")
+	changed, err := FromContext(c).Format(check)
+	if err != nil {
+		return err
+	}
+	for _, name := range changed {
+		fmt.Fprintln(c.Stdout, name)
+	}
+	if check && len(changed) > 0 {
+		return cli.Exit("configuration files are not formatted")
+	}
+	return nil
+}
+
 // Log provides the action to access logs
 func Log() cli.Action {
 	return cli.Pipeline(